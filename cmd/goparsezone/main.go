@@ -0,0 +1,53 @@
+// Command goparsezone parses a DNS zone file and prints or converts it.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	goparsezone "github.com/shuque/goparsezone"
+)
+
+func main() {
+	outFormat := flag.String("out", "pretty", "output format: pretty, tsv, json, yaml")
+	validate := flag.Bool("validate", false, "run zone-level semantic validation and report any problems")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [-out format] [-validate] <zone-file>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	filename := flag.Arg(0)
+
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error: File '%s' does not exist\n", filename)
+		os.Exit(1)
+	}
+
+	zone, err := goparsezone.ParseZone(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing zone file: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *validate {
+		errs := zone.Validate()
+		for _, verr := range errs {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", filename, verr)
+		}
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+	}
+
+	if err := zone.WriteTo(os.Stdout, *outFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing zone: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,129 @@
+// Package dnssec implements the cryptographic primitives behind DNSSEC
+// zone signing: key tag computation and RRSIG signing/verification for
+// RSASHA256, ECDSAP256SHA256, and ED25519, plus the iterated hash used
+// by NSEC3. It operates on already wire-encoded byte slices and has no
+// dependency on the goparsezone package; the glue that turns a parsed
+// *Zone into these byte slices (and the resulting signatures back into
+// RRSIG/NSEC/NSEC3 records) lives in goparsezone itself (see Zone.Sign
+// and Zone.Verify), which avoids an import cycle between the two.
+package dnssec
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+// Algorithm numbers for the DNSSEC signing algorithms this package
+// supports, per the IANA "DNS Security Algorithm Numbers" registry.
+const (
+	RSASHA256       uint8 = 8
+	ECDSAP256SHA256 uint8 = 13
+	ED25519         uint8 = 15
+)
+
+// KeyTag computes the key tag of a DNSKEY from its wire-format RDATA
+// (flags, protocol, algorithm, and public key, in that order), per the
+// algorithm in RFC 4034 Appendix B. It assumes algorithm != 1
+// (RSA/MD5), which none of the algorithms this package signs with use.
+func KeyTag(dnskeyRDATA []byte) uint16 {
+	var ac uint32
+	for i, b := range dnskeyRDATA {
+		if i&1 == 0 {
+			ac += uint32(b) << 8
+		} else {
+			ac += uint32(b)
+		}
+	}
+	ac += (ac >> 16) & 0xffff
+	return uint16(ac & 0xffff)
+}
+
+// Sign produces a signature over data using priv, whose concrete type
+// must match alg: *rsa.PrivateKey for RSASHA256, *ecdsa.PrivateKey
+// (P-256) for ECDSAP256SHA256, or ed25519.PrivateKey for ED25519.
+func Sign(alg uint8, priv crypto.Signer, data []byte) ([]byte, error) {
+	switch alg {
+	case RSASHA256:
+		key, ok := priv.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("dnssec: RSASHA256 requires an *rsa.PrivateKey, got %T", priv)
+		}
+		sum := sha256.Sum256(data)
+		return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	case ECDSAP256SHA256:
+		key, ok := priv.(*ecdsa.PrivateKey)
+		if !ok || key.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("dnssec: ECDSAP256SHA256 requires an *ecdsa.PrivateKey on P-256, got %T", priv)
+		}
+		sum := sha256.Sum256(data)
+		r, s, err := ecdsa.Sign(rand.Reader, key, sum[:])
+		if err != nil {
+			return nil, err
+		}
+		return encodeECDSASignature(r, s), nil
+	case ED25519:
+		key, ok := priv.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("dnssec: ED25519 requires an ed25519.PrivateKey, got %T", priv)
+		}
+		return ed25519.Sign(key, data), nil
+	default:
+		return nil, fmt.Errorf("dnssec: unsupported signing algorithm %d", alg)
+	}
+}
+
+// Verify checks sig against data using pub, whose concrete type must
+// match alg in the same way as Sign's priv argument.
+func Verify(alg uint8, pub crypto.PublicKey, data, sig []byte) error {
+	switch alg {
+	case RSASHA256:
+		key, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("dnssec: RSASHA256 requires an *rsa.PublicKey, got %T", pub)
+		}
+		sum := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], sig)
+	case ECDSAP256SHA256:
+		key, ok := pub.(*ecdsa.PublicKey)
+		if !ok || key.Curve != elliptic.P256() {
+			return fmt.Errorf("dnssec: ECDSAP256SHA256 requires an *ecdsa.PublicKey on P-256, got %T", pub)
+		}
+		if len(sig) != 64 {
+			return fmt.Errorf("dnssec: ECDSAP256SHA256 signature must be 64 bytes, got %d", len(sig))
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		sum := sha256.Sum256(data)
+		if !ecdsa.Verify(key, sum[:], r, s) {
+			return fmt.Errorf("dnssec: signature verification failed")
+		}
+		return nil
+	case ED25519:
+		key, ok := pub.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("dnssec: ED25519 requires an ed25519.PublicKey, got %T", pub)
+		}
+		if !ed25519.Verify(key, data, sig) {
+			return fmt.Errorf("dnssec: signature verification failed")
+		}
+		return nil
+	default:
+		return fmt.Errorf("dnssec: unsupported signing algorithm %d", alg)
+	}
+}
+
+// encodeECDSASignature renders r and s as the fixed-width big-endian
+// pair required by RFC 6605 section 4 (32 bytes each for P-256).
+func encodeECDSASignature(r, s *big.Int) []byte {
+	buf := make([]byte, 64)
+	r.FillBytes(buf[:32])
+	s.FillBytes(buf[32:])
+	return buf
+}
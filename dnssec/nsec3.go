@@ -0,0 +1,36 @@
+package dnssec
+
+import (
+	"crypto/sha1"
+	"encoding/base32"
+)
+
+// HashNSEC3 computes the iterated SHA-1 hash of name (given in canonical
+// wire form) used as an NSEC3 owner/next-hashed-owner name, per RFC 5155
+// section 5: IH(0) = H(name | salt), IH(k) = H(IH(k-1) | salt), and the
+// result is IH(iterations).
+func HashNSEC3(name []byte, iterations uint16, salt []byte) []byte {
+	digest := hashOnce(name, salt)
+	for i := uint16(0); i < iterations; i++ {
+		digest = hashOnce(digest, salt)
+	}
+	return digest
+}
+
+func hashOnce(data, salt []byte) []byte {
+	h := sha1.New()
+	h.Write(data)
+	h.Write(salt)
+	return h.Sum(nil)
+}
+
+// Base32HexEncode renders data the way NSEC3 owner/hash names are
+// written in presentation format: base32hex, upper case, no padding.
+func Base32HexEncode(data []byte) string {
+	return base32.HexEncoding.WithPadding(base32.NoPadding).EncodeToString(data)
+}
+
+// Base32HexDecode is the inverse of Base32HexEncode.
+func Base32HexDecode(s string) ([]byte, error) {
+	return base32.HexEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+}
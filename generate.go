@@ -0,0 +1,220 @@
+package goparsezone
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// expandGenerate expands a $GENERATE directive into the DNSRecords it
+// describes. The directive has the form:
+//
+//	$GENERATE start-stop[/step] lhs [ttl] [class] type rhs
+//
+// where lhs and rhs may contain ${offset,width,base} (or bare $) counter
+// substitutions, following BIND's $GENERATE syntax.
+func expandGenerate(e *entry, s *parseState) ([]DNSRecord, error) {
+	if len(e.args) < 3 {
+		return nil, e.errorf("$GENERATE: expected at least range, lhs and type")
+	}
+
+	start, stop, step, err := parseGenerateRange(e.args[0])
+	if err != nil {
+		return nil, e.errorf("$GENERATE: %v", err)
+	}
+
+	lhs := e.args[1]
+	rest := e.args[2:]
+
+	var ttl, class, rrtype string
+	i := 0
+	if i < len(rest) && ttl == "" && isTTLField(rest[i]) {
+		ttl = rest[i]
+		i++
+	}
+	if i < len(rest) && isClass(rest[i]) {
+		class = rest[i]
+		i++
+	}
+	if i >= len(rest) {
+		return nil, e.errorf("$GENERATE: missing record type")
+	}
+	rrtype = strings.ToUpper(rest[i])
+	i++
+	rhs := strings.Join(rest[i:], " ")
+	if rhs == "" {
+		return nil, e.errorf("$GENERATE: missing RHS")
+	}
+
+	recTTL := s.ttl
+	if ttl != "" {
+		recTTL, err = parseTTL(ttl)
+		if err != nil {
+			return nil, e.errorf("$GENERATE: invalid TTL %q: %v", ttl, err)
+		}
+	} else if !s.ttlSet && s.zone.DefaultTTL != 0 {
+		recTTL = s.zone.DefaultTTL
+	}
+	recClass := s.class
+	if class != "" {
+		recClass = class
+	}
+
+	var recs []DNSRecord
+	for n := start; (step > 0 && n <= stop) || (step < 0 && n >= stop); n += step {
+		name, err := substituteGenerate(lhs, n)
+		if err != nil {
+			return nil, e.errorf("$GENERATE: lhs: %v", err)
+		}
+		rdata, err := substituteGenerate(rhs, n)
+		if err != nil {
+			return nil, e.errorf("$GENERATE: rhs: %v", err)
+		}
+
+		rr, err := parseRR(rrtype, strings.Fields(rdata), s.zone.Origin)
+		if err != nil {
+			return nil, e.errorf("$GENERATE: invalid %s RDATA: %v", rrtype, err)
+		}
+
+		recs = append(recs, DNSRecord{
+			Name:   qualifyName(name, s.zone.Origin),
+			TTL:    recTTL,
+			Class:  recClass,
+			Type:   rrtype,
+			RData:  rdata,
+			RR:     rr,
+			Origin: s.zone.Origin,
+			Line:   e.line,
+		})
+	}
+
+	return recs, nil
+}
+
+// parseGenerateRange parses the "start-stop[/step]" range prefix of a
+// $GENERATE directive.
+func parseGenerateRange(spec string) (start, stop, step int, err error) {
+	step = 1
+	if idx := strings.Index(spec, "/"); idx != -1 {
+		step, err = strconv.Atoi(spec[idx+1:])
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid step in range %q", spec)
+		}
+		spec = spec[:idx]
+	}
+
+	parts := strings.SplitN(spec, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, 0, fmt.Errorf("invalid range %q, expected start-stop", spec)
+	}
+	start, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid range start %q", parts[0])
+	}
+	stop, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid range stop %q", parts[1])
+	}
+	if step == 0 {
+		return 0, 0, 0, fmt.Errorf("step cannot be zero")
+	}
+	if step > 0 && start > stop {
+		return 0, 0, 0, fmt.Errorf("range start %d is after stop %d", start, stop)
+	}
+	if step < 0 && start < stop {
+		return 0, 0, 0, fmt.Errorf("range start %d is before stop %d with negative step", start, stop)
+	}
+	return start, stop, step, nil
+}
+
+// substituteGenerate expands every $ and ${offset,width,base} placeholder
+// in pattern for counter value n. A bare "$" is shorthand for "${0,0,d}".
+// A literal "$" is written as "\$".
+func substituteGenerate(pattern string, n int) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c == '\\' && i+1 < len(pattern) && pattern[i+1] == '$' {
+			sb.WriteByte('$')
+			i++
+			continue
+		}
+		if c != '$' {
+			sb.WriteByte(c)
+			continue
+		}
+
+		if i+1 < len(pattern) && pattern[i+1] == '{' {
+			end := strings.IndexByte(pattern[i+2:], '}')
+			if end == -1 {
+				return "", fmt.Errorf("unterminated ${...} in %q", pattern)
+			}
+			spec := pattern[i+2 : i+2+end]
+			out, err := formatGenerateCounter(spec, n)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(out)
+			i += 2 + end
+			continue
+		}
+
+		sb.WriteString(strconv.Itoa(n))
+	}
+	return sb.String(), nil
+}
+
+// formatGenerateCounter formats n according to an "offset,width,base"
+// ${...} spec: offset is added to n, width is the minimum zero-padded
+// field width, and base is one of d (decimal), o (octal), x/X (hex).
+func formatGenerateCounter(spec string, n int) (string, error) {
+	fields := strings.Split(spec, ",")
+	offset, width, base := 0, 0, "d"
+
+	if len(fields) >= 1 && fields[0] != "" {
+		v, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return "", fmt.Errorf("invalid offset in ${%s}", spec)
+		}
+		offset = v
+	}
+	if len(fields) >= 2 && fields[1] != "" {
+		v, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return "", fmt.Errorf("invalid width in ${%s}", spec)
+		}
+		width = v
+	}
+	if len(fields) >= 3 && fields[2] != "" {
+		base = fields[2]
+	}
+
+	value := n + offset
+
+	var s string
+	switch base {
+	case "d":
+		s = strconv.Itoa(value)
+	case "o":
+		s = strconv.FormatInt(int64(value), 8)
+	case "x":
+		s = strconv.FormatInt(int64(value), 16)
+	case "X":
+		s = strings.ToUpper(strconv.FormatInt(int64(value), 16))
+	default:
+		return "", fmt.Errorf("invalid base %q in ${%s}", base, spec)
+	}
+
+	if neg := strings.HasPrefix(s, "-"); neg {
+		s = s[1:]
+		for len(s) < width {
+			s = "0" + s
+		}
+		s = "-" + s
+	} else {
+		for len(s) < width {
+			s = "0" + s
+		}
+	}
+	return s, nil
+}
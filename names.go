@@ -0,0 +1,50 @@
+package goparsezone
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IsDomainName reports whether name is a syntactically valid domain name
+// per RFC 1035 section 2.3.4 (label and total length limits) and the
+// RFC 3696 section 2 guidance on which characters are safe in a zone
+// file, without needing escaping. The root name "." is always valid.
+func IsDomainName(name string) bool {
+	if name == "." {
+		return true
+	}
+	if len(name) == 0 || len(name) > 255 {
+		return false
+	}
+
+	labels := strings.Split(strings.TrimSuffix(name, "."), ".")
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 {
+			return false
+		}
+		for _, r := range label {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			case r == '-' || r == '_' || r == '*':
+			default:
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseUint parses s as an unsigned integer of the given bit size,
+// shared by the various RR parsers that need bounds-checked numeric
+// fields (e.g. MX preference, SOA timers, DNSSEC flags).
+func parseUint(s string, bitSize int) (uint64, error) {
+	return strconv.ParseUint(s, 10, bitSize)
+}
+
+func requireFields(fields []string, n int, rrtype string) error {
+	if len(fields) < n {
+		return fmt.Errorf("%s: expected at least %d fields, got %d", rrtype, n, len(fields))
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package goparsezone
+
+import "fmt"
+
+// PrintZone prints the parsed zone to stdout in a simple human-readable
+// debug format, one block per record.
+func (z *Zone) PrintZone() {
+	fmt.Printf("Zone: %s\n", z.Origin)
+	if z.DefaultTTL != 0 {
+		fmt.Printf("Default TTL: %d\n", z.DefaultTTL)
+	}
+	fmt.Printf("Records: %d\n\n", len(z.Records))
+
+	for i, record := range z.Records {
+		fmt.Printf("Record %d:\n", i+1)
+		fmt.Printf("  Name: %s\n", record.Name)
+		fmt.Printf("  TTL: %d\n", record.TTL)
+		fmt.Printf("  Class: %s\n", record.Class)
+		fmt.Printf("  Type: %s\n", record.Type)
+		fmt.Printf("  RData: %s\n", record.RData)
+		fmt.Println()
+	}
+}
@@ -0,0 +1,105 @@
+package goparsezone
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// RR is implemented by every typed resource record body goparsezone knows
+// how to parse from presentation format. String returns the RDATA alone
+// in presentation format, with domain names fully qualified and TXT/CAA
+// values quoted; it can therefore differ from DNSRecord.RData, which keeps
+// the fields as the tokenizer produced them.
+type RR interface {
+	String() string
+}
+
+// Unknown holds the RFC 3597 "generic" RDATA encoding (\# <len> <hex>)
+// used for record types goparsezone has no specific struct for, and for
+// any RDATA a zone file chooses to write generically.
+type Unknown struct {
+	Data []byte
+}
+
+func (rr *Unknown) String() string {
+	return fmt.Sprintf("\\# %d %s", len(rr.Data), hex.EncodeToString(rr.Data))
+}
+
+// parseRR parses the whitespace/quote-delimited RDATA fields of a record
+// of the given type into a typed RR, qualifying any domain-name fields
+// against origin. RDATA written in the RFC 3597 generic form is parsed
+// into an Unknown for any type. Otherwise it returns (nil, nil) for record
+// types with no typed support, in which case callers fall back to the raw
+// DNSRecord.RData string; it returns a non-nil error when the generic form
+// is malformed or when the type is recognized but the RDATA does not
+// match what that type requires.
+func parseRR(rrtype string, fields []string, origin string) (RR, error) {
+	if len(fields) > 0 && fields[0] == `\#` {
+		return parseUnknown(fields)
+	}
+
+	switch rrtype {
+	case "A":
+		return parseA(fields)
+	case "AAAA":
+		return parseAAAA(fields)
+	case "NS":
+		return parseNS(fields, origin)
+	case "CNAME":
+		return parseCNAME(fields, origin)
+	case "PTR":
+		return parsePTR(fields, origin)
+	case "MX":
+		return parseMX(fields, origin)
+	case "SOA":
+		return parseSOA(fields, origin)
+	case "TXT":
+		return parseTXT(fields)
+	case "SRV":
+		return parseSRV(fields, origin)
+	case "CAA":
+		return parseCAA(fields)
+	case "DS":
+		return parseDS(fields)
+	case "DNSKEY":
+		return parseDNSKEY(fields)
+	case "RRSIG":
+		return parseRRSIG(fields, origin)
+	case "NSEC":
+		return parseNSEC(fields, origin)
+	case "NSEC3":
+		return parseNSEC3(fields)
+	case "TLSA":
+		return parseTLSA(fields)
+	case "SVCB":
+		return parseSVCB(fields, origin)
+	case "HTTPS":
+		svcb, err := parseSVCB(fields, origin)
+		if err != nil {
+			return nil, err
+		}
+		return &HTTPS{SVCB: *svcb}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// parseUnknown decodes the RFC 3597 generic RDATA form "\# <len> <hex>".
+func parseUnknown(fields []string) (*Unknown, error) {
+	if len(fields) < 2 {
+		return nil, fmt.Errorf(`\#: expected "\# <len> <hex>"`)
+	}
+	n, err := parseUint(fields[1], 32)
+	if err != nil {
+		return nil, fmt.Errorf(`\#: invalid length %q: %w`, fields[1], err)
+	}
+	data, err := hex.DecodeString(strings.Join(fields[2:], ""))
+	if err != nil {
+		return nil, fmt.Errorf(`\#: invalid hex: %w`, err)
+	}
+	if uint64(len(data)) != n {
+		return nil, fmt.Errorf(`\#: length %d does not match declared %d`, len(data), n)
+	}
+	return &Unknown{Data: data}, nil
+}
@@ -0,0 +1,571 @@
+package goparsezone
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeParseYYYYMMDDHHmmSS parses an RRSIG-style absolute timestamp
+// (YYYYMMDDHHmmSS, always UTC) into seconds since the epoch.
+func timeParseYYYYMMDDHHmmSS(s string) (uint64, error) {
+	t, err := time.Parse("20060102150405", s)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(t.Unix()), nil
+}
+
+// A is the RDATA of an A record: a single IPv4 address.
+type A struct {
+	Address net.IP
+}
+
+func (rr *A) String() string { return rr.Address.String() }
+
+func parseA(fields []string) (*A, error) {
+	if err := requireFields(fields, 1, "A"); err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(fields[0])
+	if ip == nil || ip.To4() == nil {
+		return nil, fmt.Errorf("A: invalid IPv4 address %q", fields[0])
+	}
+	return &A{Address: ip.To4()}, nil
+}
+
+// AAAA is the RDATA of an AAAA record: a single IPv6 address.
+type AAAA struct {
+	Address net.IP
+}
+
+func (rr *AAAA) String() string { return rr.Address.String() }
+
+func parseAAAA(fields []string) (*AAAA, error) {
+	if err := requireFields(fields, 1, "AAAA"); err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(fields[0])
+	if ip == nil || ip.To4() != nil {
+		return nil, fmt.Errorf("AAAA: invalid IPv6 address %q", fields[0])
+	}
+	return &AAAA{Address: ip}, nil
+}
+
+// NS is the RDATA of an NS record.
+type NS struct {
+	Host string
+}
+
+func (rr *NS) String() string { return rr.Host }
+
+func parseNS(fields []string, origin string) (*NS, error) {
+	if err := requireFields(fields, 1, "NS"); err != nil {
+		return nil, err
+	}
+	host := qualifyName(fields[0], origin)
+	if !IsDomainName(host) {
+		return nil, fmt.Errorf("NS: invalid domain name %q", host)
+	}
+	return &NS{Host: host}, nil
+}
+
+// CNAME is the RDATA of a CNAME record.
+type CNAME struct {
+	Target string
+}
+
+func (rr *CNAME) String() string { return rr.Target }
+
+func parseCNAME(fields []string, origin string) (*CNAME, error) {
+	if err := requireFields(fields, 1, "CNAME"); err != nil {
+		return nil, err
+	}
+	target := qualifyName(fields[0], origin)
+	if !IsDomainName(target) {
+		return nil, fmt.Errorf("CNAME: invalid domain name %q", target)
+	}
+	return &CNAME{Target: target}, nil
+}
+
+// PTR is the RDATA of a PTR record.
+type PTR struct {
+	Target string
+}
+
+func (rr *PTR) String() string { return rr.Target }
+
+func parsePTR(fields []string, origin string) (*PTR, error) {
+	if err := requireFields(fields, 1, "PTR"); err != nil {
+		return nil, err
+	}
+	target := qualifyName(fields[0], origin)
+	if !IsDomainName(target) {
+		return nil, fmt.Errorf("PTR: invalid domain name %q", target)
+	}
+	return &PTR{Target: target}, nil
+}
+
+// MX is the RDATA of an MX record.
+type MX struct {
+	Preference uint16
+	Exchange   string
+}
+
+func (rr *MX) String() string { return fmt.Sprintf("%d %s", rr.Preference, rr.Exchange) }
+
+func parseMX(fields []string, origin string) (*MX, error) {
+	if err := requireFields(fields, 2, "MX"); err != nil {
+		return nil, err
+	}
+	pref, err := parseUint(fields[0], 16)
+	if err != nil {
+		return nil, fmt.Errorf("MX: invalid preference %q: %w", fields[0], err)
+	}
+	exchange := qualifyName(fields[1], origin)
+	if !IsDomainName(exchange) {
+		return nil, fmt.Errorf("MX: invalid domain name %q", exchange)
+	}
+	return &MX{Preference: uint16(pref), Exchange: exchange}, nil
+}
+
+// SOA is the RDATA of a SOA record.
+type SOA struct {
+	MName   string
+	RName   string
+	Serial  uint32
+	Refresh uint32
+	Retry   uint32
+	Expire  uint32
+	Minimum uint32
+}
+
+func (rr *SOA) String() string {
+	return fmt.Sprintf("%s %s %d %d %d %d %d", rr.MName, rr.RName, rr.Serial, rr.Refresh, rr.Retry, rr.Expire, rr.Minimum)
+}
+
+func parseSOA(fields []string, origin string) (*SOA, error) {
+	if err := requireFields(fields, 7, "SOA"); err != nil {
+		return nil, err
+	}
+	mname := qualifyName(fields[0], origin)
+	rname := qualifyName(fields[1], origin)
+	if !IsDomainName(mname) {
+		return nil, fmt.Errorf("SOA: invalid MNAME %q", mname)
+	}
+	if !IsDomainName(rname) {
+		return nil, fmt.Errorf("SOA: invalid RNAME %q", rname)
+	}
+
+	vals := make([]uint32, 5)
+	names := []string{"serial", "refresh", "retry", "expire", "minimum"}
+	for i, name := range names {
+		v, err := parseUint(fields[2+i], 32)
+		if err != nil {
+			return nil, fmt.Errorf("SOA: invalid %s %q: %w", name, fields[2+i], err)
+		}
+		vals[i] = uint32(v)
+	}
+
+	return &SOA{
+		MName:   mname,
+		RName:   rname,
+		Serial:  vals[0],
+		Refresh: vals[1],
+		Retry:   vals[2],
+		Expire:  vals[3],
+		Minimum: vals[4],
+	}, nil
+}
+
+// TXT is the RDATA of a TXT record: one or more character-strings, each
+// already unescaped by the tokenizer and stripped of its quotes.
+type TXT struct {
+	Strings []string
+}
+
+func (rr *TXT) String() string {
+	parts := make([]string, len(rr.Strings))
+	for i, s := range rr.Strings {
+		var sb strings.Builder
+		sb.WriteByte('"')
+		for j := 0; j < len(s); j++ {
+			switch c := s[j]; {
+			case c == '"' || c == '\\':
+				sb.WriteByte('\\')
+				sb.WriteByte(c)
+			case c < 0x20 || c >= 0x7f:
+				fmt.Fprintf(&sb, "\\%03d", c)
+			default:
+				sb.WriteByte(c)
+			}
+		}
+		sb.WriteByte('"')
+		parts[i] = sb.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+func parseTXT(fields []string) (*TXT, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("TXT: expected at least one character-string")
+	}
+	return &TXT{Strings: append([]string(nil), fields...)}, nil
+}
+
+// SRV is the RDATA of an SRV record (RFC 2782).
+type SRV struct {
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	Target   string
+}
+
+func (rr *SRV) String() string {
+	return fmt.Sprintf("%d %d %d %s", rr.Priority, rr.Weight, rr.Port, rr.Target)
+}
+
+func parseSRV(fields []string, origin string) (*SRV, error) {
+	if err := requireFields(fields, 4, "SRV"); err != nil {
+		return nil, err
+	}
+	priority, err := parseUint(fields[0], 16)
+	if err != nil {
+		return nil, fmt.Errorf("SRV: invalid priority %q: %w", fields[0], err)
+	}
+	weight, err := parseUint(fields[1], 16)
+	if err != nil {
+		return nil, fmt.Errorf("SRV: invalid weight %q: %w", fields[1], err)
+	}
+	port, err := parseUint(fields[2], 16)
+	if err != nil {
+		return nil, fmt.Errorf("SRV: invalid port %q: %w", fields[2], err)
+	}
+	target := qualifyName(fields[3], origin)
+	if !IsDomainName(target) {
+		return nil, fmt.Errorf("SRV: invalid target %q", target)
+	}
+	return &SRV{Priority: uint16(priority), Weight: uint16(weight), Port: uint16(port), Target: target}, nil
+}
+
+// CAA is the RDATA of a CAA record (RFC 6844).
+type CAA struct {
+	Flag  uint8
+	Tag   string
+	Value string
+}
+
+func (rr *CAA) String() string { return fmt.Sprintf("%d %s %q", rr.Flag, rr.Tag, rr.Value) }
+
+func parseCAA(fields []string) (*CAA, error) {
+	if err := requireFields(fields, 3, "CAA"); err != nil {
+		return nil, err
+	}
+	flag, err := parseUint(fields[0], 8)
+	if err != nil {
+		return nil, fmt.Errorf("CAA: invalid flag %q: %w", fields[0], err)
+	}
+	return &CAA{Flag: uint8(flag), Tag: fields[1], Value: strings.Join(fields[2:], " ")}, nil
+}
+
+// DS is the RDATA of a DS record (RFC 4034).
+type DS struct {
+	KeyTag     uint16
+	Algorithm  uint8
+	DigestType uint8
+	Digest     string // hex-encoded
+}
+
+func (rr *DS) String() string {
+	return fmt.Sprintf("%d %d %d %s", rr.KeyTag, rr.Algorithm, rr.DigestType, rr.Digest)
+}
+
+func parseDS(fields []string) (*DS, error) {
+	if err := requireFields(fields, 4, "DS"); err != nil {
+		return nil, err
+	}
+	keyTag, err := parseUint(fields[0], 16)
+	if err != nil {
+		return nil, fmt.Errorf("DS: invalid key tag %q: %w", fields[0], err)
+	}
+	algo, err := parseUint(fields[1], 8)
+	if err != nil {
+		return nil, fmt.Errorf("DS: invalid algorithm %q: %w", fields[1], err)
+	}
+	digestType, err := parseUint(fields[2], 8)
+	if err != nil {
+		return nil, fmt.Errorf("DS: invalid digest type %q: %w", fields[2], err)
+	}
+	digest := strings.Join(fields[3:], "")
+	if _, err := hex.DecodeString(digest); err != nil {
+		return nil, fmt.Errorf("DS: invalid digest hex: %w", err)
+	}
+	return &DS{KeyTag: uint16(keyTag), Algorithm: uint8(algo), DigestType: uint8(digestType), Digest: strings.ToLower(digest)}, nil
+}
+
+// DNSKEY is the RDATA of a DNSKEY record (RFC 4034). PrivateKey is never
+// populated by the parser; it is set by callers that build a DNSKEY by
+// hand to pass to Zone.Sign, which needs the matching private key to
+// produce RRSIG records. It holds an *rsa.PrivateKey, *ecdsa.PrivateKey,
+// or ed25519.PrivateKey depending on Algorithm.
+type DNSKEY struct {
+	Flags      uint16
+	Protocol   uint8
+	Algorithm  uint8
+	PublicKey  string // base64-encoded
+	PrivateKey crypto.Signer
+}
+
+func (rr *DNSKEY) String() string {
+	return fmt.Sprintf("%d %d %d %s", rr.Flags, rr.Protocol, rr.Algorithm, rr.PublicKey)
+}
+
+func parseDNSKEY(fields []string) (*DNSKEY, error) {
+	if err := requireFields(fields, 4, "DNSKEY"); err != nil {
+		return nil, err
+	}
+	flags, err := parseUint(fields[0], 16)
+	if err != nil {
+		return nil, fmt.Errorf("DNSKEY: invalid flags %q: %w", fields[0], err)
+	}
+	protocol, err := parseUint(fields[1], 8)
+	if err != nil {
+		return nil, fmt.Errorf("DNSKEY: invalid protocol %q: %w", fields[1], err)
+	}
+	algo, err := parseUint(fields[2], 8)
+	if err != nil {
+		return nil, fmt.Errorf("DNSKEY: invalid algorithm %q: %w", fields[2], err)
+	}
+	key := strings.Join(fields[3:], "")
+	if _, err := base64.StdEncoding.DecodeString(key); err != nil {
+		return nil, fmt.Errorf("DNSKEY: invalid public key base64: %w", err)
+	}
+	return &DNSKEY{Flags: uint16(flags), Protocol: uint8(protocol), Algorithm: uint8(algo), PublicKey: key}, nil
+}
+
+// RRSIG is the RDATA of an RRSIG record (RFC 4034).
+type RRSIG struct {
+	TypeCovered string
+	Algorithm   uint8
+	Labels      uint8
+	OriginalTTL uint32
+	Expiration  uint32
+	Inception   uint32
+	KeyTag      uint16
+	SignerName  string
+	Signature   string // base64-encoded
+}
+
+func (rr *RRSIG) String() string {
+	return fmt.Sprintf("%s %d %d %d %d %d %d %s %s",
+		rr.TypeCovered, rr.Algorithm, rr.Labels, rr.OriginalTTL,
+		rr.Expiration, rr.Inception, rr.KeyTag, rr.SignerName, rr.Signature)
+}
+
+func parseRRSIG(fields []string, origin string) (*RRSIG, error) {
+	if err := requireFields(fields, 9, "RRSIG"); err != nil {
+		return nil, err
+	}
+	algo, err := parseUint(fields[1], 8)
+	if err != nil {
+		return nil, fmt.Errorf("RRSIG: invalid algorithm %q: %w", fields[1], err)
+	}
+	labels, err := parseUint(fields[2], 8)
+	if err != nil {
+		return nil, fmt.Errorf("RRSIG: invalid labels %q: %w", fields[2], err)
+	}
+	origTTL, err := parseUint(fields[3], 32)
+	if err != nil {
+		return nil, fmt.Errorf("RRSIG: invalid original TTL %q: %w", fields[3], err)
+	}
+	expiration, err := parseRRSIGTime(fields[4])
+	if err != nil {
+		return nil, fmt.Errorf("RRSIG: invalid expiration %q: %w", fields[4], err)
+	}
+	inception, err := parseRRSIGTime(fields[5])
+	if err != nil {
+		return nil, fmt.Errorf("RRSIG: invalid inception %q: %w", fields[5], err)
+	}
+	keyTag, err := parseUint(fields[6], 16)
+	if err != nil {
+		return nil, fmt.Errorf("RRSIG: invalid key tag %q: %w", fields[6], err)
+	}
+	signer := qualifyName(fields[7], origin)
+	if !IsDomainName(signer) {
+		return nil, fmt.Errorf("RRSIG: invalid signer name %q", signer)
+	}
+	sig := strings.Join(fields[8:], "")
+	if _, err := base64.StdEncoding.DecodeString(sig); err != nil {
+		return nil, fmt.Errorf("RRSIG: invalid signature base64: %w", err)
+	}
+
+	return &RRSIG{
+		TypeCovered: strings.ToUpper(fields[0]),
+		Algorithm:   uint8(algo),
+		Labels:      uint8(labels),
+		OriginalTTL: uint32(origTTL),
+		Expiration:  uint32(expiration),
+		Inception:   uint32(inception),
+		KeyTag:      uint16(keyTag),
+		SignerName:  signer,
+		Signature:   sig,
+	}, nil
+}
+
+// parseRRSIGTime parses an RRSIG timestamp, which is written either as
+// seconds since the epoch or as YYYYMMDDHHmmSS.
+func parseRRSIGTime(s string) (uint64, error) {
+	if len(s) == 14 {
+		if _, err := strconv.ParseUint(s, 10, 64); err == nil {
+			t, err := timeParseYYYYMMDDHHmmSS(s)
+			if err == nil {
+				return t, nil
+			}
+		}
+	}
+	return strconv.ParseUint(s, 10, 32)
+}
+
+// NSEC is the RDATA of an NSEC record (RFC 4034).
+type NSEC struct {
+	NextDomain  string
+	TypeBitMaps []string
+}
+
+func (rr *NSEC) String() string {
+	return rr.NextDomain + " " + strings.Join(rr.TypeBitMaps, " ")
+}
+
+func parseNSEC(fields []string, origin string) (*NSEC, error) {
+	if err := requireFields(fields, 1, "NSEC"); err != nil {
+		return nil, err
+	}
+	next := qualifyName(fields[0], origin)
+	if !IsDomainName(next) {
+		return nil, fmt.Errorf("NSEC: invalid next domain name %q", next)
+	}
+	return &NSEC{NextDomain: next, TypeBitMaps: append([]string(nil), fields[1:]...)}, nil
+}
+
+// NSEC3 is the RDATA of an NSEC3 record (RFC 5155).
+type NSEC3 struct {
+	Hash                uint8
+	Flags               uint8
+	Iterations          uint16
+	Salt                string // hex, "-" for empty
+	NextHashedOwnerName string // base32hex, no padding
+	TypeBitMaps         []string
+}
+
+func (rr *NSEC3) String() string {
+	return fmt.Sprintf("%d %d %d %s %s %s", rr.Hash, rr.Flags, rr.Iterations, rr.Salt, rr.NextHashedOwnerName, strings.Join(rr.TypeBitMaps, " "))
+}
+
+func parseNSEC3(fields []string) (*NSEC3, error) {
+	if err := requireFields(fields, 5, "NSEC3"); err != nil {
+		return nil, err
+	}
+	hash, err := parseUint(fields[0], 8)
+	if err != nil {
+		return nil, fmt.Errorf("NSEC3: invalid hash algorithm %q: %w", fields[0], err)
+	}
+	flags, err := parseUint(fields[1], 8)
+	if err != nil {
+		return nil, fmt.Errorf("NSEC3: invalid flags %q: %w", fields[1], err)
+	}
+	iterations, err := parseUint(fields[2], 16)
+	if err != nil {
+		return nil, fmt.Errorf("NSEC3: invalid iterations %q: %w", fields[2], err)
+	}
+	salt := fields[3]
+	if salt != "-" {
+		if _, err := hex.DecodeString(salt); err != nil {
+			return nil, fmt.Errorf("NSEC3: invalid salt hex: %w", err)
+		}
+	}
+	return &NSEC3{
+		Hash:                uint8(hash),
+		Flags:               uint8(flags),
+		Iterations:          uint16(iterations),
+		Salt:                salt,
+		NextHashedOwnerName: fields[4],
+		TypeBitMaps:         append([]string(nil), fields[5:]...),
+	}, nil
+}
+
+// TLSA is the RDATA of a TLSA record (RFC 6698).
+type TLSA struct {
+	Usage        uint8
+	Selector     uint8
+	MatchingType uint8
+	Certificate  string // hex-encoded
+}
+
+func (rr *TLSA) String() string {
+	return fmt.Sprintf("%d %d %d %s", rr.Usage, rr.Selector, rr.MatchingType, rr.Certificate)
+}
+
+func parseTLSA(fields []string) (*TLSA, error) {
+	if err := requireFields(fields, 4, "TLSA"); err != nil {
+		return nil, err
+	}
+	usage, err := parseUint(fields[0], 8)
+	if err != nil {
+		return nil, fmt.Errorf("TLSA: invalid usage %q: %w", fields[0], err)
+	}
+	selector, err := parseUint(fields[1], 8)
+	if err != nil {
+		return nil, fmt.Errorf("TLSA: invalid selector %q: %w", fields[1], err)
+	}
+	matchingType, err := parseUint(fields[2], 8)
+	if err != nil {
+		return nil, fmt.Errorf("TLSA: invalid matching type %q: %w", fields[2], err)
+	}
+	cert := strings.Join(fields[3:], "")
+	if _, err := hex.DecodeString(cert); err != nil {
+		return nil, fmt.Errorf("TLSA: invalid certificate hex: %w", err)
+	}
+	return &TLSA{Usage: uint8(usage), Selector: uint8(selector), MatchingType: uint8(matchingType), Certificate: strings.ToLower(cert)}, nil
+}
+
+// SVCB is the RDATA of an SVCB record (RFC 9460). SvcParams are kept as
+// their raw "key=value" (or bare key) presentation-format tokens rather
+// than decoded per key, since the set of keys is still growing.
+type SVCB struct {
+	Priority  uint16
+	Target    string
+	SvcParams []string
+}
+
+func (rr *SVCB) String() string {
+	return fmt.Sprintf("%d %s %s", rr.Priority, rr.Target, strings.Join(rr.SvcParams, " "))
+}
+
+func parseSVCB(fields []string, origin string) (*SVCB, error) {
+	if err := requireFields(fields, 2, "SVCB"); err != nil {
+		return nil, err
+	}
+	priority, err := parseUint(fields[0], 16)
+	if err != nil {
+		return nil, fmt.Errorf("SVCB: invalid priority %q: %w", fields[0], err)
+	}
+	target := fields[1]
+	if target != "." {
+		target = qualifyName(target, origin)
+	}
+	if !IsDomainName(target) {
+		return nil, fmt.Errorf("SVCB: invalid target %q", target)
+	}
+	return &SVCB{Priority: uint16(priority), Target: target, SvcParams: append([]string(nil), fields[2:]...)}, nil
+}
+
+// HTTPS is the RDATA of an HTTPS record, which is SVCB with a fixed
+// RRTYPE meaning (RFC 9460 section 9).
+type HTTPS struct {
+	SVCB
+}
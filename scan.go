@@ -0,0 +1,502 @@
+package goparsezone
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// tokenKind classifies the raw lexical items produced by the character
+// level scanner, modeled on the token set used by miekg/dns's zscan.go.
+type tokenKind int
+
+const (
+	tokField   tokenKind = iota // a bare or quoted whitespace-delimited field
+	tokNewline                  // an unparenthesized end of line
+	tokBlank                    // a line consisting only of leading whitespace (owner continuation)
+	tokEOF
+)
+
+// lexToken is one item produced by the fileLexer's character-level state
+// machine, before it has been classified as OWNER/TTL/CLASS/RRTYPE/RDATA.
+type lexToken struct {
+	kind tokenKind
+	text string
+	line int
+	col  int
+}
+
+// fileLexer turns the raw bytes of a zone file into a stream of lexTokens,
+// tracking parenthesis depth (which turns newlines into plain whitespace),
+// quoted strings, and backslash escapes, all in a single pass.
+type fileLexer struct {
+	filename string
+	closer   io.Closer
+	br       *bufio.Reader
+	line     int
+	col      int
+
+	parenDepth int
+
+	// atLineStart is true from the beginning of the file, and again after
+	// every unparenthesized newline, until the first field of that line
+	// (if any) has been consumed. It distinguishes leading whitespace
+	// that may introduce a blank-owner continuation line from ordinary
+	// whitespace between fields later in the same line.
+	atLineStart bool
+
+	// comments accumulates the text of every ";" comment seen since the
+	// start of the current entry, so it can be attached to the record the
+	// entry produces.
+	comments []string
+}
+
+// newLexer wraps r for tokenizing; filename is used only to annotate
+// errors and is not opened or read directly.
+func newLexer(r io.Reader, filename string) *fileLexer {
+	return &fileLexer{filename: filename, br: bufio.NewReader(r), line: 1, atLineStart: true}
+}
+
+// openLexer opens filename and returns a lexer that owns the resulting
+// file handle; Close releases it. Used for the top-level file named to
+// ParseZone and for files pulled in via $INCLUDE.
+func openLexer(filename string) (*fileLexer, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	lx := newLexer(f, filename)
+	lx.closer = f
+	return lx, nil
+}
+
+func (lx *fileLexer) Close() error {
+	if lx.closer != nil {
+		return lx.closer.Close()
+	}
+	return nil
+}
+
+func (lx *fileLexer) readRune() (rune, error) {
+	r, _, err := lx.br.ReadRune()
+	if err == nil {
+		lx.col++
+	}
+	return r, err
+}
+
+// nextToken runs the character-level state machine one token forward.
+func (lx *fileLexer) nextToken() (lexToken, error) {
+	var sb strings.Builder
+	startCol := lx.col + 1
+
+	for {
+		r, err := lx.readRune()
+		if err == io.EOF {
+			if sb.Len() > 0 {
+				return lexToken{kind: tokField, text: sb.String(), line: lx.line, col: startCol}, nil
+			}
+			return lexToken{kind: tokEOF, line: lx.line, col: startCol}, nil
+		}
+		if err != nil {
+			return lexToken{}, err
+		}
+
+		switch r {
+		case '\n':
+			if sb.Len() > 0 {
+				// Leave the newline unconsumed; it is processed (and the
+				// line counter advanced) on the next call.
+				lx.unread()
+				return lexToken{kind: tokField, text: sb.String(), line: lx.line, col: startCol}, nil
+			}
+			startLine := lx.line
+			lx.line++
+			lx.col = 0
+			if lx.parenDepth > 0 {
+				continue
+			}
+			lx.atLineStart = true
+			return lexToken{kind: tokNewline, line: startLine, col: startCol}, nil
+
+		case ' ', '\t', '\r':
+			if sb.Len() > 0 {
+				return lexToken{kind: tokField, text: sb.String(), line: lx.line, col: startCol}, nil
+			}
+			startCol = lx.col + 1
+			if lx.atLineStart && lx.parenDepth == 0 {
+				// Leading whitespace with nothing buffered yet: this may be
+				// a blank-owner continuation line. Peek to see whether the
+				// rest of the line is empty/comment (BLANK) or a record.
+				return lx.blankOrContinue()
+			}
+			continue
+
+		case ';':
+			if sb.Len() > 0 {
+				lx.unread()
+				return lexToken{kind: tokField, text: sb.String(), line: lx.line, col: startCol}, nil
+			}
+			lx.comments = append(lx.comments, lx.readComment())
+			startCol = lx.col + 1
+			continue
+
+		case '"':
+			lx.atLineStart = false
+			s, err := lx.readQuoted()
+			if err != nil {
+				return lexToken{}, err
+			}
+			sb.WriteString(s)
+			return lexToken{kind: tokField, text: sb.String(), line: lx.line, col: startCol}, nil
+
+		case '(':
+			if sb.Len() > 0 {
+				lx.unread()
+				return lexToken{kind: tokField, text: sb.String(), line: lx.line, col: startCol}, nil
+			}
+			lx.parenDepth++
+			startCol = lx.col + 1
+			continue
+
+		case ')':
+			if sb.Len() > 0 {
+				lx.unread()
+				return lexToken{kind: tokField, text: sb.String(), line: lx.line, col: startCol}, nil
+			}
+			if lx.parenDepth > 0 {
+				lx.parenDepth--
+			}
+			startCol = lx.col + 1
+			continue
+
+		case '\\':
+			lx.atLineStart = false
+			esc, err := lx.readEscape()
+			if err != nil {
+				return lexToken{}, err
+			}
+			if esc == "#" && sb.Len() == 0 {
+				// A standalone "\#" is the RFC 3597 generic RDATA marker;
+				// keep it escaped so parseRR can recognize it.
+				if next, perr := lx.br.Peek(1); perr != nil || strings.IndexByte(" \t\r\n", next[0]) != -1 {
+					esc = `\#`
+				}
+			}
+			sb.WriteString(esc)
+			continue
+
+		default:
+			lx.atLineStart = false
+			sb.WriteRune(r)
+		}
+	}
+}
+
+// blankOrContinue is called on leading whitespace at the start of a line;
+// it decides between emitting a BLANK token (comment/empty continuation)
+// and simply continuing the scan for a blank-owner record line.
+func (lx *fileLexer) blankOrContinue() (lexToken, error) {
+	for {
+		r, err := lx.readRune()
+		if err == io.EOF {
+			return lexToken{kind: tokEOF, line: lx.line}, nil
+		}
+		if err != nil {
+			return lexToken{}, err
+		}
+		switch r {
+		case ' ', '\t', '\r':
+			continue
+		case '\n':
+			line := lx.line
+			lx.line++
+			lx.col = 0
+			return lexToken{kind: tokBlank, line: line}, nil
+		case ';':
+			lx.comments = append(lx.comments, lx.readComment())
+			continue
+		default:
+			lx.atLineStart = false
+			lx.unread()
+			return lexToken{kind: tokBlank, text: "cont", line: lx.line, col: lx.col + 1}, nil
+		}
+	}
+}
+
+// readComment consumes up to (but not including) the end of line and
+// returns the comment text with the leading ";" and surrounding
+// whitespace stripped.
+func (lx *fileLexer) readComment() string {
+	var sb strings.Builder
+	for {
+		r, err := lx.readRune()
+		if err != nil || r == '\n' {
+			if r == '\n' {
+				lx.unread()
+			}
+			return strings.TrimSpace(sb.String())
+		}
+		sb.WriteRune(r)
+	}
+}
+
+func (lx *fileLexer) readQuoted() (string, error) {
+	var sb strings.Builder
+	for {
+		r, _, err := lx.br.ReadRune()
+		if err != nil {
+			return "", fmt.Errorf("line %d: unterminated quoted string", lx.line)
+		}
+		switch r {
+		case '"':
+			return sb.String(), nil
+		case '\n':
+			return "", fmt.Errorf("line %d: newline in quoted string", lx.line)
+		case '\\':
+			esc, err := lx.readEscape()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(esc)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+// readEscape decodes an RFC 1035 section 5.1 escape: either \DDD (a decimal
+// byte value) or \X (a literal character).
+func (lx *fileLexer) readEscape() (string, error) {
+	r, _, err := lx.br.ReadRune()
+	if err != nil {
+		return "", fmt.Errorf("line %d: dangling escape", lx.line)
+	}
+	if r >= '0' && r <= '9' {
+		digits := []rune{r}
+		for i := 0; i < 2; i++ {
+			d, _, err := lx.br.ReadRune()
+			if err != nil || d < '0' || d > '9' {
+				if err == nil {
+					lx.unread()
+				}
+				break
+			}
+			digits = append(digits, d)
+		}
+		if len(digits) != 3 {
+			return "", fmt.Errorf("line %d: invalid \\DDD escape", lx.line)
+		}
+		n, err := strconv.Atoi(string(digits))
+		if err != nil || n > 255 {
+			return "", fmt.Errorf("line %d: invalid \\DDD escape", lx.line)
+		}
+		return string([]byte{byte(n)}), nil
+	}
+	return string(r), nil
+}
+
+func (lx *fileLexer) unread() {
+	_ = lx.br.UnreadRune()
+}
+
+// entry is one logical record/directive, assembled from lexTokens: either
+// a directive ($ORIGIN, $TTL, $INCLUDE, $GENERATE) with its raw arguments,
+// or a record with OWNER/TTL/CLASS/RRTYPE/RDATA fields classified from the
+// token stream the same way regardless of whether it spanned one line or
+// several parenthesized ones.
+type entry struct {
+	directive string
+	args      []string
+
+	owner, ttl, class, rrtype, rdata string
+	rdataFields                      []string
+	comments                         []string
+	line, col                        int
+	filename                         string
+}
+
+func (e *entry) directiveArg(i int) (string, error) {
+	if i >= len(e.args) {
+		return "", e.errorf("%s: missing argument %d", e.directive, i+1)
+	}
+	return e.args[i], nil
+}
+
+func (e *entry) errorf(format string, args ...interface{}) error {
+	return &ParseError{File: e.filename, Line: e.line, Column: e.col, Err: fmt.Sprintf(format, args...)}
+}
+
+// NextEntry reads tokens until it has assembled the next directive or
+// record entry, or returns (nil, nil) at end of file.
+func (lx *fileLexer) NextEntry() (*entry, error) {
+	var fields []string
+	startLine, startCol := 0, 0
+	blankOwner := false
+
+	for {
+		tok, err := lx.nextToken()
+		if err != nil {
+			return nil, &lexFatalError{&ParseError{File: lx.filename, Line: lx.line, Err: err.Error()}}
+		}
+
+		switch tok.kind {
+		case tokEOF:
+			if len(fields) == 0 {
+				return nil, nil
+			}
+			return lx.classify(fields, startLine, startCol, blankOwner)
+
+		case tokNewline:
+			if len(fields) == 0 {
+				lx.comments = nil // a comment-only line belongs to no record
+				continue
+			}
+			return lx.classify(fields, startLine, startCol, blankOwner)
+
+		case tokBlank:
+			if len(fields) == 0 && tok.text != "cont" {
+				lx.comments = nil
+				continue // a pure comment/blank line: skip it
+			}
+			if len(fields) == 0 {
+				blankOwner = true
+			}
+			continue
+
+		case tokField:
+			if len(fields) == 0 {
+				startLine, startCol = tok.line, tok.col
+			}
+			fields = append(fields, tok.text)
+		}
+	}
+}
+
+// classify assigns the whitespace-delimited fields of one entry to
+// OWNER/TTL/CLASS/RRTYPE/RDATA (for records) or directive name/args (for
+// directives), following the same field-order heuristics BIND uses.
+func (lx *fileLexer) classify(fields []string, line, col int, blankOwner bool) (*entry, error) {
+	comments := lx.comments
+	lx.comments = nil
+
+	if strings.HasPrefix(fields[0], "$") {
+		return &entry{
+			directive: strings.ToUpper(fields[0]),
+			args:      fields[1:],
+			comments:  comments,
+			line:      line,
+			col:       col,
+			filename:  lx.filename,
+		}, nil
+	}
+
+	e := &entry{line: line, col: col, comments: comments, filename: lx.filename}
+	i := 0
+	if !blankOwner {
+		e.owner = fields[0]
+		i = 1
+	}
+
+	for i < len(fields) {
+		f := fields[i]
+		if e.ttl == "" && e.rrtype == "" && isTTLField(f) {
+			e.ttl = f
+			i++
+			continue
+		}
+		if e.class == "" && e.rrtype == "" && isClass(f) {
+			e.class = f
+			i++
+			continue
+		}
+		break
+	}
+
+	if i >= len(fields) {
+		return nil, &ParseError{File: lx.filename, Line: line, Column: col, Err: "missing record type"}
+	}
+	e.rrtype = strings.ToUpper(fields[i])
+	i++
+	e.rdataFields = fields[i:]
+	e.rdata = strings.Join(e.rdataFields, " ")
+	return e, nil
+}
+
+func isTTLField(s string) bool {
+	_, err := parseTTL(s)
+	return err == nil
+}
+
+// isClass reports whether s names one of the DNS classes recognized in
+// zone file presentation format.
+func isClass(s string) bool {
+	switch strings.ToUpper(s) {
+	case "IN", "CH", "HS", "NONE", "ANY":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseTTL parses a TTL field, accepting both bare seconds and BIND's
+// unit-suffixed form (1h, 2d, 1w, ...).
+func parseTTL(ttlStr string) (uint32, error) {
+	s := strings.ToUpper(strings.TrimSpace(ttlStr))
+	if s == "" {
+		return 0, fmt.Errorf("empty TTL")
+	}
+
+	multiplier := uint32(1)
+	switch s[len(s)-1] {
+	case 'S':
+		multiplier, s = 1, s[:len(s)-1]
+	case 'M':
+		multiplier, s = 60, s[:len(s)-1]
+	case 'H':
+		multiplier, s = 3600, s[:len(s)-1]
+	case 'D':
+		multiplier, s = 86400, s[:len(s)-1]
+	case 'W':
+		multiplier, s = 604800, s[:len(s)-1]
+	}
+
+	value, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(value) * multiplier, nil
+}
+
+// qualifyName expands "@" to origin and appends origin to any non-FQDN
+// owner name, the same inheritance rule RFC 1035 presentation format uses.
+func qualifyName(name, origin string) string {
+	if name == "@" && origin != "" {
+		return origin
+	}
+	if !strings.HasSuffix(name, ".") && origin != "" {
+		return name + "." + origin
+	}
+	return name
+}
+
+// resolveInclude resolves a $INCLUDE target relative to the directory of
+// the file that contains the directive, matching BIND's behavior.
+func resolveInclude(fromFile, incFile string) string {
+	if filepath.IsAbs(incFile) {
+		return incFile
+	}
+	return filepath.Join(filepath.Dir(fromFile), incFile)
+}
+
+func absPath(p string) string {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return p
+	}
+	return abs
+}
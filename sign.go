@@ -0,0 +1,384 @@
+package goparsezone
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shuque/goparsezone/dnssec"
+)
+
+// SignOptions controls how Zone.Sign builds RRSIG records, and the
+// NSEC/NSEC3 chain that accompanies them, for a zone.
+type SignOptions struct {
+	Inception  uint32 // RRSIG inception time, seconds since the epoch
+	Expiration uint32 // RRSIG expiration time, seconds since the epoch
+	NSEC3      bool   // generate an NSEC3 chain instead of NSEC
+	Iterations uint16 // NSEC3 hash iterations
+	Salt       string // NSEC3 salt, hex-encoded, "" for no salt
+	OptOut     bool   // NSEC3 opt-out flag
+}
+
+// rrset is an owner name/type group of records, the unit DNSSEC signs
+// and the unit an NSEC/NSEC3 type bit map is built from.
+type rrset struct {
+	name    string
+	rrtype  string
+	ttl     uint32
+	members []DNSRecord
+}
+
+// Sign computes RRSIG records over every RRset in the zone using keys,
+// generates an NSEC or NSEC3 chain (per opts), signs that chain too, and
+// appends all of it to z.Records. Each key must carry a PrivateKey
+// matching its Algorithm; DNSKEYs parsed from a zone file by ParseZone
+// never do, since zone files only ever contain public key material —
+// callers that want to sign a zone build the DNSKEY(s) to sign it with
+// by hand and pass them here, and Sign also inserts them into the zone
+// as its DNSKEY RRset.
+func (z *Zone) Sign(keys []DNSKEY, opts SignOptions) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("dnssec: Sign: no keys supplied")
+	}
+
+	for _, key := range keys {
+		k := key
+		z.Records = append(z.Records, DNSRecord{
+			Name: z.Origin, TTL: z.soaMinimum(), Class: "IN", Type: "DNSKEY",
+			RR: &k, RData: k.String(),
+		})
+	}
+
+	if err := z.signRRsets(keys, opts); err != nil {
+		return err
+	}
+
+	var chain []DNSRecord
+	var err error
+	if opts.NSEC3 {
+		chain, err = z.buildNSEC3Chain(opts)
+	} else {
+		chain, err = z.buildNSECChain()
+	}
+	if err != nil {
+		return err
+	}
+	z.Records = append(z.Records, chain...)
+
+	return z.signRRsets(keys, opts)
+}
+
+// signRRsets signs every RRset not already covered by an RRSIG.
+func (z *Zone) signRRsets(keys []DNSKEY, opts SignOptions) error {
+	covered := make(map[string]bool)
+	for _, r := range z.Records {
+		if r.Type == "RRSIG" {
+			if rrsig, ok := r.RR.(*RRSIG); ok {
+				covered[r.Name+"/"+strings.ToUpper(rrsig.TypeCovered)+"/"+fmt.Sprint(rrsig.KeyTag)] = true
+			}
+		}
+	}
+
+	var newRecords []DNSRecord
+	for _, set := range z.rrsets() {
+		for _, key := range keys {
+			keyTag, err := dnskeyKeyTag(&key)
+			if err != nil {
+				return fmt.Errorf("dnssec: Sign: %w", err)
+			}
+			if covered[set.name+"/"+set.rrtype+"/"+fmt.Sprint(keyTag)] {
+				continue
+			}
+			rrsig, err := signRRset(z.Origin, set, key, keyTag, opts)
+			if err != nil {
+				return err
+			}
+			newRecords = append(newRecords, *rrsig)
+		}
+	}
+	z.Records = append(z.Records, newRecords...)
+	return nil
+}
+
+// rrsets groups z.Records (excluding existing RRSIGs, which are never
+// themselves signed) by owner name and type.
+func (z *Zone) rrsets() []rrset {
+	index := make(map[string]int)
+	var out []rrset
+	for _, r := range z.Records {
+		if r.Type == "RRSIG" {
+			continue
+		}
+		key := r.Name + "/" + r.Type
+		if i, ok := index[key]; ok {
+			out[i].members = append(out[i].members, r)
+			continue
+		}
+		index[key] = len(out)
+		out = append(out, rrset{name: r.Name, rrtype: r.Type, ttl: r.TTL, members: []DNSRecord{r}})
+	}
+	return out
+}
+
+// signRRset produces the RRSIG covering set, signed by key.
+func signRRset(signerName string, set rrset, key DNSKEY, keyTag uint16, opts SignOptions) (*DNSRecord, error) {
+	if key.PrivateKey == nil {
+		return nil, fmt.Errorf("dnssec: Sign: key tag %d has no PrivateKey", keyTag)
+	}
+
+	labels := nameLabelCount(set.name)
+	signedData, err := buildSignedData(set.name, set.rrtype, key.Algorithm, labels,
+		set.ttl, opts.Expiration, opts.Inception, keyTag, signerName, set.members)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := dnssec.Sign(key.Algorithm, key.PrivateKey, signedData)
+	if err != nil {
+		return nil, fmt.Errorf("dnssec: Sign: %s %s: %w", set.name, set.rrtype, err)
+	}
+
+	rrsig := &RRSIG{
+		TypeCovered: strings.ToUpper(set.rrtype),
+		Algorithm:   key.Algorithm,
+		Labels:      labels,
+		OriginalTTL: set.ttl,
+		Expiration:  opts.Expiration,
+		Inception:   opts.Inception,
+		KeyTag:      keyTag,
+		SignerName:  signerName,
+		Signature:   base64.StdEncoding.EncodeToString(sig),
+	}
+	return &DNSRecord{
+		Name: set.name, TTL: set.ttl, Class: "IN", Type: "RRSIG",
+		RR: rrsig, RData: rrsig.String(),
+	}, nil
+}
+
+// buildSignedData assembles the byte stream an RRSIG's signature covers,
+// per RFC 4034 section 3.1.8.1: the RRSIG RDATA up to (but excluding)
+// the signature itself, followed by each member of the RRset in
+// canonical form and canonical order.
+func buildSignedData(owner, typeCovered string, algorithm, labels uint8, origTTL, expiration, inception uint32, keyTag uint16, signerName string, members []DNSRecord) ([]byte, error) {
+	typeCoveredNum, err := dnsTypeNumber(typeCovered)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 18)
+	binary.BigEndian.PutUint16(data[0:2], typeCoveredNum)
+	data[2] = algorithm
+	data[3] = labels
+	binary.BigEndian.PutUint32(data[4:8], origTTL)
+	binary.BigEndian.PutUint32(data[8:12], expiration)
+	binary.BigEndian.PutUint32(data[12:16], inception)
+	binary.BigEndian.PutUint16(data[16:18], keyTag)
+	data = append(data, canonicalWireName(signerName)...)
+
+	ordered, err := canonicalOrder(members)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerWire := canonicalWireName(owner)
+	for _, rdata := range ordered {
+		data = append(data, ownerWire...)
+		header := make([]byte, 10)
+		binary.BigEndian.PutUint16(header[0:2], typeCoveredNum)
+		binary.BigEndian.PutUint16(header[2:4], 1) // class IN
+		binary.BigEndian.PutUint32(header[4:8], origTTL)
+		binary.BigEndian.PutUint16(header[8:10], uint16(len(rdata)))
+		data = append(data, header...)
+		data = append(data, rdata...)
+	}
+	return data, nil
+}
+
+// dnskeyKeyTag computes key's key tag from its canonical wire RDATA.
+func dnskeyKeyTag(key *DNSKEY) (uint16, error) {
+	rdata, err := canonicalDNSKEYRData(key)
+	if err != nil {
+		return 0, fmt.Errorf("DNSKEY: %w", err)
+	}
+	return dnssec.KeyTag(rdata), nil
+}
+
+// nameLabelCount returns the number of labels in name, for the RRSIG
+// Labels field; the root name has zero labels, and a wildcard's own
+// leftmost label is excluded per RFC 4034 section 3.1.3.
+func nameLabelCount(name string) uint8 {
+	trimmed := strings.TrimSuffix(name, ".")
+	if trimmed == "" {
+		return 0
+	}
+	labels := strings.Split(trimmed, ".")
+	if labels[0] == "*" {
+		labels = labels[1:]
+	}
+	return uint8(len(labels))
+}
+
+// soaMinimum returns the zone's SOA MINIMUM field, used as the TTL for
+// synthesized NSEC/NSEC3 records (RFC 4035 section 2.3), falling back to
+// the zone's default TTL if no typed SOA is present.
+func (z *Zone) soaMinimum() uint32 {
+	for _, r := range z.Records {
+		if r.Type == "SOA" {
+			if soa, ok := r.RR.(*SOA); ok {
+				return soa.Minimum
+			}
+		}
+	}
+	return z.DefaultTTL
+}
+
+// ownerIndex returns every distinct owner name in the zone, in
+// first-seen order, along with the record types present at each.
+func (z *Zone) ownerIndex() ([]string, map[string][]string) {
+	seen := make(map[string]bool)
+	var names []string
+	types := make(map[string][]string)
+	for _, r := range z.Records {
+		if r.Type == "RRSIG" {
+			continue
+		}
+		if !seen[r.Name] {
+			seen[r.Name] = true
+			names = append(names, r.Name)
+		}
+		types[r.Name] = append(types[r.Name], r.Type)
+	}
+	return names, types
+}
+
+// dedupTypes deduplicates and sorts types by DNS type number, the form
+// NSEC/NSEC3 type bit maps are listed in.
+func dedupTypes(types []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, t := range types {
+		t = strings.ToUpper(t)
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		ni, _ := dnsTypeNumber(out[i])
+		nj, _ := dnsTypeNumber(out[j])
+		return ni < nj
+	})
+	return out
+}
+
+// canonicalNameLess reports whether a sorts before b in the canonical
+// owner-name ordering of RFC 4034 section 6.1: labels compared
+// right-to-left, case-insensitively.
+func canonicalNameLess(a, b string) bool {
+	la := reversedLabels(a)
+	lb := reversedLabels(b)
+	for i := 0; i < len(la) && i < len(lb); i++ {
+		if la[i] != lb[i] {
+			return la[i] < lb[i]
+		}
+	}
+	return len(la) < len(lb)
+}
+
+func reversedLabels(name string) []string {
+	trimmed := strings.ToLower(strings.TrimSuffix(name, "."))
+	if trimmed == "" {
+		return nil
+	}
+	labels := strings.Split(trimmed, ".")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+	return labels
+}
+
+// buildNSECChain returns one NSEC record per distinct owner name in the
+// zone, each pointing at the next name in canonical order (wrapping
+// around to the first).
+func (z *Zone) buildNSECChain() ([]DNSRecord, error) {
+	names, typesByName := z.ownerIndex()
+	if len(names) == 0 {
+		return nil, nil
+	}
+	sort.Slice(names, func(i, j int) bool { return canonicalNameLess(names[i], names[j]) })
+
+	ttl := z.soaMinimum()
+	out := make([]DNSRecord, len(names))
+	for i, name := range names {
+		next := names[(i+1)%len(names)]
+		types := dedupTypes(append(append([]string{}, typesByName[name]...), "NSEC", "RRSIG"))
+		nsec := &NSEC{NextDomain: next, TypeBitMaps: types}
+		out[i] = DNSRecord{Name: name, TTL: ttl, Class: "IN", Type: "NSEC", RR: nsec, RData: nsec.String()}
+	}
+	return out, nil
+}
+
+// buildNSEC3Chain returns one NSEC3 record per distinct owner name in
+// the zone, hashed per opts and ordered by hash, each pointing at the
+// next hash in the chain (wrapping around to the first).
+func (z *Zone) buildNSEC3Chain(opts SignOptions) ([]DNSRecord, error) {
+	names, typesByName := z.ownerIndex()
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var salt []byte
+	if opts.Salt != "" {
+		var err error
+		salt, err = hex.DecodeString(opts.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("dnssec: NSEC3: invalid salt: %w", err)
+		}
+	}
+	saltHex := "-"
+	if len(salt) > 0 {
+		saltHex = hex.EncodeToString(salt)
+	}
+
+	type hashedOwner struct {
+		original string
+		hash     []byte
+		types    []string
+	}
+	owners := make([]hashedOwner, len(names))
+	for i, name := range names {
+		owners[i] = hashedOwner{
+			original: name,
+			hash:     dnssec.HashNSEC3(canonicalWireName(name), opts.Iterations, salt),
+			types:    typesByName[name],
+		}
+	}
+	sort.Slice(owners, func(i, j int) bool { return compareBytes(owners[i].hash, owners[j].hash) < 0 })
+
+	flags := uint8(0)
+	if opts.OptOut {
+		flags = 1
+	}
+	ttl := z.soaMinimum()
+
+	out := make([]DNSRecord, len(owners))
+	for i, o := range owners {
+		next := owners[(i+1)%len(owners)]
+		types := dedupTypes(append(append([]string{}, o.types...), "NSEC3", "RRSIG"))
+		nsec3 := &NSEC3{
+			Hash:                1,
+			Flags:               flags,
+			Iterations:          opts.Iterations,
+			Salt:                saltHex,
+			NextHashedOwnerName: dnssec.Base32HexEncode(next.hash),
+			TypeBitMaps:         types,
+		}
+		owner := dnssec.Base32HexEncode(o.hash) + "." + z.Origin
+		out[i] = DNSRecord{Name: owner, TTL: ttl, Class: "IN", Type: "NSEC3", RR: nsec3, RData: nsec3.String()}
+	}
+	return out, nil
+}
@@ -0,0 +1,277 @@
+package goparsezone
+
+import (
+	"fmt"
+	"io"
+)
+
+// ParseError describes a problem encountered while tokenizing or
+// interpreting a zone file, with enough position information to point a
+// user at the offending line.
+type ParseError struct {
+	File   string
+	Line   int
+	Column int
+	Err    string
+}
+
+func (e *ParseError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Err)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Err)
+}
+
+// lexFatalError marks a ParseError that came from the character-level
+// scanner itself (a malformed escape, an unterminated quote, an I/O
+// error) rather than from classifying an otherwise well-formed entry.
+// After one of these, the lexer's position is unreliable, so run stops
+// instead of trying to resynchronize on the next entry.
+type lexFatalError struct {
+	*ParseError
+}
+
+// Token is one item produced by ParseZoneReader: either a successfully
+// parsed record (RR), possibly with its trailing ";" comment attached, or
+// a ParseError describing why a record could not be parsed. Parsing
+// continues after an Error token for a bad entry, so a caller ranging over
+// the channel sees every record that *could* be recovered; a lexer-level
+// error (malformed escape, unterminated quoted string, read error) ends
+// the scan of the file it occurred in.
+type Token struct {
+	RR      *DNSRecord
+	Comment string
+	Error   *ParseError
+}
+
+// ParseZoneReader tokenizes the zone data read from r (whose records are
+// relative to origin, and whose own name is filename, used for $INCLUDE
+// resolution and error messages) and streams the results on the returned
+// channel, which is closed once parsing finishes. Unlike ParseZone, it
+// does not abort on a bad record: it emits a Token with Error set and moves
+// on to the next entry, so a caller can process zones far too large to
+// hold in memory and decide for itself how to handle malformed records.
+// Only a lexer-level error stops the scan of the file it occurred in.
+func ParseZoneReader(r io.Reader, origin, filename string) <-chan Token {
+	_, ch := parseToChan(r, origin, filename)
+	return ch
+}
+
+// parseToChan drives the shared parseState engine over r and returns both
+// the Zone being populated (Origin/DefaultTTL only; Records is left empty
+// for callers to fill in from the Token stream as they see fit) and the
+// channel of Tokens. The Zone is safe to read once the channel is closed,
+// since the producing goroutine no longer touches it at that point.
+func parseToChan(r io.Reader, origin, filename string) (*Zone, <-chan Token) {
+	zone := &Zone{Origin: origin}
+	ch := make(chan Token, 64)
+
+	s := &parseState{
+		zone:      zone,
+		class:     "IN",
+		including: map[string]bool{absPath(filename): true},
+		out:       ch,
+	}
+
+	go func() {
+		defer close(ch)
+		s.run(newLexer(r, filename))
+	}()
+
+	return zone, ch
+}
+
+// parseState carries the mutable state threaded through recursive
+// $INCLUDE parsing: the zone being built (for Origin/DefaultTTL and
+// owner/TTL/class inheritance), the output Token channel, and the set of
+// files already on the include stack, used to detect $INCLUDE cycles.
+type parseState struct {
+	zone      *Zone
+	name      string
+	ttl       uint32
+	ttlSet    bool
+	class     string
+	including map[string]bool
+	out       chan<- Token
+}
+
+// run tokenizes lx, emitting a Token for every record it builds (or error
+// it hits), and recurses into $INCLUDE directives and $GENERATE expansion
+// as it goes. Errors at the entry/record level are reported but do not
+// stop the scan; only a fatal problem with lx itself (a lexer-level
+// error, not a classification error) ends the loop early.
+func (s *parseState) run(lx *fileLexer) {
+	defer lx.Close()
+
+	for {
+		e, err := lx.NextEntry()
+		if err != nil {
+			if fatal, ok := err.(*lexFatalError); ok {
+				s.emitError(fatal.ParseError)
+				return
+			}
+			s.emitError(err)
+			continue
+		}
+		if e == nil {
+			return
+		}
+
+		switch {
+		case e.directive == "$ORIGIN":
+			o, err := e.directiveArg(0)
+			if err != nil {
+				s.emitError(err)
+				continue
+			}
+			s.zone.Origin = o
+
+		case e.directive == "$TTL":
+			ttl, err := parseTTL(mustArg(e, 0))
+			if err != nil {
+				s.emitError(e.errorf("invalid $TTL value: %v", err))
+				continue
+			}
+			s.zone.DefaultTTL = ttl
+			s.ttl = ttl
+			s.ttlSet = true
+
+		case e.directive == "$INCLUDE":
+			s.handleInclude(e, lx.filename)
+
+		case e.directive == "$GENERATE":
+			recs, err := expandGenerate(e, s)
+			if err != nil {
+				s.emitError(err)
+				continue
+			}
+			for i := range recs {
+				s.out <- Token{RR: &recs[i]}
+			}
+
+		case e.directive != "":
+			s.emitError(e.errorf("unsupported directive %q", e.directive))
+
+		default:
+			rec, err := s.buildRecord(e)
+			if err != nil {
+				s.emitError(err)
+				continue
+			}
+			s.out <- Token{RR: rec, Comment: joinComments(e.comments)}
+		}
+	}
+}
+
+// handleInclude resolves a $INCLUDE <file> [origin] directive relative to
+// the directory of the including file, guards against include cycles, and
+// recursively tokenizes the included file onto the same channel.
+func (s *parseState) handleInclude(e *entry, fromFile string) {
+	incFile, err := e.directiveArg(0)
+	if err != nil {
+		s.emitError(err)
+		return
+	}
+	incOrigin := s.zone.Origin
+	if a, err := e.directiveArg(1); err == nil {
+		incOrigin = a
+	}
+
+	resolved := resolveInclude(fromFile, incFile)
+	abs := absPath(resolved)
+	if s.including[abs] {
+		s.emitError(e.errorf("$INCLUDE cycle detected: %s", resolved))
+		return
+	}
+
+	lx, err := openLexer(resolved)
+	if err != nil {
+		s.emitError(e.errorf("$INCLUDE %s: %v", resolved, err))
+		return
+	}
+
+	s.including[abs] = true
+	savedOrigin := s.zone.Origin
+	s.zone.Origin = incOrigin
+	s.run(lx)
+	s.zone.Origin = savedOrigin
+	delete(s.including, abs)
+}
+
+// buildRecord turns a tokenized record entry into a DNSRecord, applying
+// ownername/TTL/class inheritance from the preceding record per RFC 1035.
+func (s *parseState) buildRecord(e *entry) (*DNSRecord, error) {
+	if e.owner != "" {
+		s.name = qualifyName(e.owner, s.zone.Origin)
+	}
+	if s.name == "" {
+		return nil, e.errorf("missing owner name and no previous owner to inherit")
+	}
+
+	if e.ttl != "" {
+		ttl, err := parseTTL(e.ttl)
+		if err != nil {
+			return nil, e.errorf("invalid TTL %q: %v", e.ttl, err)
+		}
+		s.ttl = ttl
+		s.ttlSet = true
+	} else if !s.ttlSet && s.zone.DefaultTTL != 0 {
+		s.ttl = s.zone.DefaultTTL
+		s.ttlSet = true
+	}
+
+	if e.class != "" {
+		s.class = e.class
+	}
+
+	if e.rrtype == "" {
+		return nil, e.errorf("missing record type")
+	}
+
+	rr, err := parseRR(e.rrtype, e.rdataFields, s.zone.Origin)
+	if err != nil {
+		return nil, e.errorf("invalid %s RDATA: %v", e.rrtype, err)
+	}
+
+	return &DNSRecord{
+		Name:     s.name,
+		TTL:      s.ttl,
+		Class:    s.class,
+		Type:     e.rrtype,
+		RData:    e.rdata,
+		RR:       rr,
+		Origin:   s.zone.Origin,
+		Line:     e.line,
+		Comments: e.comments,
+	}, nil
+}
+
+func (s *parseState) emitError(err error) {
+	if pe, ok := err.(*ParseError); ok {
+		s.out <- Token{Error: pe}
+		return
+	}
+	s.out <- Token{Error: &ParseError{Err: err.Error()}}
+}
+
+// mustArg returns the i'th directive argument, or "" if absent; directive
+// arity is validated by the caller against what each directive requires.
+func mustArg(e *entry, i int) string {
+	a, _ := e.directiveArg(i)
+	return a
+}
+
+func joinComments(comments []string) string {
+	switch len(comments) {
+	case 0:
+		return ""
+	case 1:
+		return comments[0]
+	default:
+		out := comments[0]
+		for _, c := range comments[1:] {
+			out += "; " + c
+		}
+		return out
+	}
+}
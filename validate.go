@@ -0,0 +1,180 @@
+package goparsezone
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validate runs a zone-level semantic check over z and returns every
+// problem found; it does not stop at the first one. It checks for a
+// missing or duplicate SOA at the apex, missing NS records at the apex,
+// CNAME records coexisting with other data at the same owner, dangling
+// in-zone targets of CNAME/MX/SRV records, missing glue for in-bailiwick
+// nameservers (at the apex or a delegation), TTL mismatches within an
+// RRset, and syntactically invalid owner names. It assumes z was
+// produced by ParseZone/ParseZoneReader. An empty zone is reported as
+// missing both its SOA and its apex NS records.
+func (z *Zone) Validate() []error {
+	var errs []error
+
+	byOwner := make(map[string][]DNSRecord)
+	owners := make(map[string]bool)
+	for _, r := range z.Records {
+		byOwner[r.Name] = append(byOwner[r.Name], r)
+		owners[r.Name] = true
+	}
+
+	errs = append(errs, z.validateApex(byOwner)...)
+	errs = append(errs, z.validateOwners(byOwner)...)
+	errs = append(errs, z.validateTargets(owners)...)
+	errs = append(errs, z.validateGlue(owners)...)
+
+	return errs
+}
+
+func (z *Zone) validateApex(byOwner map[string][]DNSRecord) []error {
+	var errs []error
+
+	apex := byOwner[z.Origin]
+	soaCount := 0
+	nsCount := 0
+	var lastLine int
+	for _, r := range apex {
+		lastLine = r.Line
+		switch r.Type {
+		case "SOA":
+			soaCount++
+			if soaCount > 1 {
+				errs = append(errs, fmt.Errorf("line %d: duplicate SOA at apex %s", r.Line, z.Origin))
+			}
+		case "NS":
+			nsCount++
+		}
+	}
+	if soaCount == 0 {
+		errs = append(errs, apexError(lastLine, "missing SOA at apex %s", z.Origin))
+	}
+	if nsCount == 0 {
+		errs = append(errs, apexError(lastLine, "missing NS records at apex %s", z.Origin))
+	}
+	return errs
+}
+
+// apexError formats an apex-level validation error. It omits the line
+// number when line is 0, which happens when the apex owner has no
+// records at all to attribute the error to (otherwise it would be
+// reported as the meaningless "line 0").
+func apexError(line int, format string, args ...interface{}) error {
+	if line == 0 {
+		return fmt.Errorf(format, args...)
+	}
+	return fmt.Errorf("line %d: "+format, append([]interface{}{line}, args...)...)
+}
+
+func (z *Zone) validateOwners(byOwner map[string][]DNSRecord) []error {
+	var errs []error
+
+	for owner, records := range byOwner {
+		if !IsDomainName(owner) {
+			errs = append(errs, fmt.Errorf("line %d: invalid owner name %q", records[0].Line, owner))
+		}
+
+		ttls := make(map[string]uint32)
+		lines := make(map[string]int)
+		hasCNAME := false
+		otherTypes := 0
+		for _, r := range records {
+			switch r.Type {
+			case "CNAME":
+				hasCNAME = true
+			case "RRSIG", "NSEC", "NSEC3", "DS":
+				// RFC 4035 section 2.3 (and RFC 2181 section 10.1 for DS
+				// at delegations) allow these to coexist with a CNAME.
+			default:
+				otherTypes++
+			}
+
+			key := r.Type
+			if prev, ok := ttls[key]; ok && prev != r.TTL {
+				errs = append(errs, fmt.Errorf("line %d: TTL %d for %s %s differs from %d at line %d",
+					r.Line, r.TTL, owner, r.Type, prev, lines[key]))
+			} else if !ok {
+				ttls[key] = r.TTL
+				lines[key] = r.Line
+			}
+		}
+		if hasCNAME && otherTypes > 0 {
+			errs = append(errs, fmt.Errorf("line %d: CNAME coexists with other data at %s", records[0].Line, owner))
+		}
+	}
+	return errs
+}
+
+// validateTargets reports CNAME/MX/SRV targets that are in-bailiwick
+// (within the zone) but do not correspond to any owner name actually
+// present in the zone. NS targets are not checked here: at a delegation
+// the nameserver legitimately has no zone data other than its glue, so
+// that case is validateGlue's job, not a dangling-target error.
+func (z *Zone) validateTargets(owners map[string]bool) []error {
+	var errs []error
+
+	checkTarget := func(r DNSRecord, target string) {
+		if target == "" || !z.inBailiwick(target) {
+			return
+		}
+		if !owners[target] {
+			errs = append(errs, fmt.Errorf("line %d: %s %s target %s does not exist in zone", r.Line, r.Type, r.Name, target))
+		}
+	}
+
+	for _, r := range z.Records {
+		switch rr := r.RR.(type) {
+		case *CNAME:
+			checkTarget(r, rr.Target)
+		case *MX:
+			checkTarget(r, rr.Exchange)
+		case *SRV:
+			checkTarget(r, rr.Target)
+		}
+	}
+	return errs
+}
+
+// validateGlue reports in-bailiwick NS targets, whether at the apex or a
+// delegation, that have no address record (A or AAAA) of their own in
+// the zone.
+func (z *Zone) validateGlue(owners map[string]bool) []error {
+	var errs []error
+
+	hasAddress := make(map[string]bool)
+	for _, r := range z.Records {
+		if r.Type == "A" || r.Type == "AAAA" {
+			hasAddress[r.Name] = true
+		}
+	}
+
+	for _, r := range z.Records {
+		if r.Type != "NS" {
+			continue
+		}
+		ns, ok := r.RR.(*NS)
+		if !ok || !z.inBailiwick(ns.Host) {
+			continue
+		}
+		if !hasAddress[ns.Host] {
+			errs = append(errs, fmt.Errorf("line %d: missing glue record for in-bailiwick nameserver %s", r.Line, ns.Host))
+		}
+	}
+	return errs
+}
+
+// inBailiwick reports whether name is equal to or a subdomain of z.Origin.
+func (z *Zone) inBailiwick(name string) bool {
+	if z.Origin == "" {
+		return false
+	}
+	if name == z.Origin {
+		return true
+	}
+	return strings.HasSuffix(name, "."+z.Origin)
+}
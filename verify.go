@@ -0,0 +1,157 @@
+package goparsezone
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+
+	"github.com/shuque/goparsezone/dnssec"
+)
+
+// Verify checks every RRSIG in the zone against the DNSKEYs also present
+// in the zone, returning every problem found rather than stopping at the
+// first: an RRSIG whose covered RRset is missing, whose key tag matches
+// no DNSKEY in the zone, or whose signature does not validate.
+func (z *Zone) Verify() []error {
+	var errs []error
+
+	keysByTag := make(map[uint16][]*DNSKEY)
+	for _, r := range z.Records {
+		if r.Type != "DNSKEY" {
+			continue
+		}
+		key, ok := r.RR.(*DNSKEY)
+		if !ok {
+			continue
+		}
+		tag, err := dnskeyKeyTag(key)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: DNSKEY at %s: %w", r.Line, r.Name, err))
+			continue
+		}
+		keysByTag[tag] = append(keysByTag[tag], key)
+	}
+
+	rrsetByKey := make(map[string]rrset)
+	for _, set := range z.rrsets() {
+		rrsetByKey[set.name+"/"+set.rrtype] = set
+	}
+
+	for _, r := range z.Records {
+		if r.Type != "RRSIG" {
+			continue
+		}
+		rrsig, ok := r.RR.(*RRSIG)
+		if !ok {
+			errs = append(errs, fmt.Errorf("line %d: RRSIG at %s has no parsed RDATA", r.Line, r.Name))
+			continue
+		}
+
+		set, ok := rrsetByKey[r.Name+"/"+rrsig.TypeCovered]
+		if !ok {
+			errs = append(errs, fmt.Errorf("line %d: RRSIG at %s covers %s but no such RRset exists", r.Line, r.Name, rrsig.TypeCovered))
+			continue
+		}
+
+		keys := keysByTag[rrsig.KeyTag]
+		if len(keys) == 0 {
+			errs = append(errs, fmt.Errorf("line %d: RRSIG at %s: no DNSKEY with key tag %d in zone", r.Line, r.Name, rrsig.KeyTag))
+			continue
+		}
+
+		if err := verifyRRSIG(r.Name, set, rrsig, keys); err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", r.Line, err))
+		}
+	}
+
+	return errs
+}
+
+// verifyRRSIG checks rrsig against set using whichever of keys matches
+// its algorithm and validates.
+func verifyRRSIG(name string, set rrset, rrsig *RRSIG, keys []*DNSKEY) error {
+	sig, err := base64.StdEncoding.DecodeString(rrsig.Signature)
+	if err != nil {
+		return fmt.Errorf("RRSIG at %s: invalid signature base64: %w", name, err)
+	}
+
+	signedData, err := buildSignedData(name, rrsig.TypeCovered, rrsig.Algorithm, rrsig.Labels,
+		rrsig.OriginalTTL, rrsig.Expiration, rrsig.Inception, rrsig.KeyTag, rrsig.SignerName, set.members)
+	if err != nil {
+		return fmt.Errorf("RRSIG at %s covering %s: %w", name, rrsig.TypeCovered, err)
+	}
+
+	var lastErr error
+	for _, key := range keys {
+		if key.Algorithm != rrsig.Algorithm {
+			lastErr = fmt.Errorf("DNSKEY algorithm %d does not match RRSIG algorithm %d", key.Algorithm, rrsig.Algorithm)
+			continue
+		}
+		pub, err := decodeDNSKEYPublicKey(key)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if err := dnssec.Verify(key.Algorithm, pub, signedData, sig); err != nil {
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("RRSIG at %s covering %s: %w", name, rrsig.TypeCovered, lastErr)
+}
+
+// decodeDNSKEYPublicKey parses key's base64 public key field into a
+// crypto.PublicKey appropriate for its Algorithm.
+func decodeDNSKEYPublicKey(key *DNSKEY) (crypto.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("DNSKEY: invalid public key base64: %w", err)
+	}
+
+	switch key.Algorithm {
+	case dnssec.RSASHA256:
+		return decodeRSAPublicKey(raw)
+	case dnssec.ECDSAP256SHA256:
+		if len(raw) != 64 {
+			return nil, fmt.Errorf("DNSKEY: ECDSAP256SHA256 public key must be 64 bytes, got %d", len(raw))
+		}
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(raw[:32]),
+			Y:     new(big.Int).SetBytes(raw[32:]),
+		}, nil
+	case dnssec.ED25519:
+		if len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("DNSKEY: ED25519 public key must be %d bytes, got %d", ed25519.PublicKeySize, len(raw))
+		}
+		return ed25519.PublicKey(raw), nil
+	default:
+		return nil, fmt.Errorf("DNSKEY: unsupported algorithm %d", key.Algorithm)
+	}
+}
+
+// decodeRSAPublicKey parses the RFC 3110 exponent/modulus encoding used
+// by DNSKEY records with an RSA algorithm.
+func decodeRSAPublicKey(raw []byte) (*rsa.PublicKey, error) {
+	if len(raw) < 3 {
+		return nil, fmt.Errorf("RSA public key too short")
+	}
+	expLen := int(raw[0])
+	offset := 1
+	if expLen == 0 {
+		expLen = int(raw[1])<<8 | int(raw[2])
+		offset = 3
+	}
+	if len(raw) < offset+expLen {
+		return nil, fmt.Errorf("RSA public key truncated")
+	}
+	exponent := new(big.Int).SetBytes(raw[offset : offset+expLen])
+	modulus := new(big.Int).SetBytes(raw[offset+expLen:])
+	return &rsa.PublicKey{N: modulus, E: int(exponent.Int64())}, nil
+}
@@ -0,0 +1,281 @@
+package goparsezone
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shuque/goparsezone/dnssec"
+)
+
+// dnsTypeNumbers maps the record type mnemonics goparsezone knows about
+// to their IANA DNS TYPE values, needed to build NSEC/NSEC3 type bitmaps
+// and RRSIG "type covered" fields in wire form.
+var dnsTypeNumbers = map[string]uint16{
+	"A":          1,
+	"NS":         2,
+	"CNAME":      5,
+	"SOA":        6,
+	"PTR":        12,
+	"MX":         15,
+	"TXT":        16,
+	"AAAA":       28,
+	"SRV":        33,
+	"DS":         43,
+	"RRSIG":      46,
+	"NSEC":       47,
+	"DNSKEY":     48,
+	"NSEC3":      50,
+	"NSEC3PARAM": 51,
+	"TLSA":       52,
+	"SVCB":       64,
+	"HTTPS":      65,
+	"CAA":        257,
+}
+
+func dnsTypeNumber(rrtype string) (uint16, error) {
+	if n, ok := dnsTypeNumbers[strings.ToUpper(rrtype)]; ok {
+		return n, nil
+	}
+	return 0, fmt.Errorf("wire: unknown record type %q", rrtype)
+}
+
+// canonicalWireName renders name in the canonical wire form required by
+// RFC 4034 section 6.2: all US-ASCII letters downcased, uncompressed,
+// length-prefixed labels terminated by the zero-length root label.
+func canonicalWireName(name string) []byte {
+	name = strings.ToLower(name)
+	trimmed := strings.TrimSuffix(name, ".")
+	if trimmed == "" {
+		return []byte{0}
+	}
+	var buf []byte
+	for _, label := range strings.Split(trimmed, ".") {
+		buf = append(buf, byte(len(label)))
+		buf = append(buf, label...)
+	}
+	return append(buf, 0)
+}
+
+// canonicalRData renders rr's RDATA in canonical wire form (RFC 4034
+// section 6.2), the representation that RRSIGs are computed over. It
+// returns an error for any RR this package does not know how to encode,
+// including a nil rr (an untyped record with no RData wire form).
+func canonicalRData(rr RR) ([]byte, error) {
+	switch v := rr.(type) {
+	case *A:
+		ip := v.Address.To4()
+		if ip == nil {
+			return nil, fmt.Errorf("wire: A record has no IPv4 address")
+		}
+		return append([]byte{}, ip...), nil
+	case *AAAA:
+		ip := v.Address.To16()
+		if ip == nil {
+			return nil, fmt.Errorf("wire: AAAA record has no IPv6 address")
+		}
+		return append([]byte{}, ip...), nil
+	case *NS:
+		return canonicalWireName(v.Host), nil
+	case *CNAME:
+		return canonicalWireName(v.Target), nil
+	case *PTR:
+		return canonicalWireName(v.Target), nil
+	case *MX:
+		buf := make([]byte, 2)
+		binary.BigEndian.PutUint16(buf, v.Preference)
+		return append(buf, canonicalWireName(v.Exchange)...), nil
+	case *SOA:
+		buf := canonicalWireName(v.MName)
+		buf = append(buf, canonicalWireName(v.RName)...)
+		tail := make([]byte, 20)
+		binary.BigEndian.PutUint32(tail[0:4], v.Serial)
+		binary.BigEndian.PutUint32(tail[4:8], v.Refresh)
+		binary.BigEndian.PutUint32(tail[8:12], v.Retry)
+		binary.BigEndian.PutUint32(tail[12:16], v.Expire)
+		binary.BigEndian.PutUint32(tail[16:20], v.Minimum)
+		return append(buf, tail...), nil
+	case *TXT:
+		var buf []byte
+		for _, s := range v.Strings {
+			if len(s) > 255 {
+				return nil, fmt.Errorf("wire: TXT: character-string longer than 255 bytes")
+			}
+			buf = append(buf, byte(len(s)))
+			buf = append(buf, s...)
+		}
+		return buf, nil
+	case *SRV:
+		buf := make([]byte, 6)
+		binary.BigEndian.PutUint16(buf[0:2], v.Priority)
+		binary.BigEndian.PutUint16(buf[2:4], v.Weight)
+		binary.BigEndian.PutUint16(buf[4:6], v.Port)
+		return append(buf, canonicalWireName(v.Target)...), nil
+	case *CAA:
+		if len(v.Tag) > 255 {
+			return nil, fmt.Errorf("wire: CAA: tag longer than 255 bytes")
+		}
+		buf := []byte{v.Flag, byte(len(v.Tag))}
+		buf = append(buf, v.Tag...)
+		return append(buf, v.Value...), nil
+	case *DS:
+		digest, err := hex.DecodeString(v.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("wire: DS: invalid digest: %w", err)
+		}
+		buf := make([]byte, 4)
+		binary.BigEndian.PutUint16(buf[0:2], v.KeyTag)
+		buf[2] = v.Algorithm
+		buf[3] = v.DigestType
+		return append(buf, digest...), nil
+	case *DNSKEY:
+		return canonicalDNSKEYRData(v)
+	case *NSEC:
+		buf := canonicalWireName(v.NextDomain)
+		bitmap, err := encodeTypeBitMaps(v.TypeBitMaps)
+		if err != nil {
+			return nil, err
+		}
+		return append(buf, bitmap...), nil
+	case *NSEC3:
+		salt := []byte{}
+		if v.Salt != "-" && v.Salt != "" {
+			var err error
+			salt, err = hex.DecodeString(v.Salt)
+			if err != nil {
+				return nil, fmt.Errorf("wire: NSEC3: invalid salt: %w", err)
+			}
+		}
+		next, err := dnssec.Base32HexDecode(v.NextHashedOwnerName)
+		if err != nil {
+			return nil, fmt.Errorf("wire: NSEC3: invalid next hashed owner name: %w", err)
+		}
+		buf := []byte{v.Hash, v.Flags, 0, 0, byte(len(salt))}
+		binary.BigEndian.PutUint16(buf[2:4], v.Iterations)
+		buf = append(buf, salt...)
+		buf = append(buf, byte(len(next)))
+		buf = append(buf, next...)
+		bitmap, err := encodeTypeBitMaps(v.TypeBitMaps)
+		if err != nil {
+			return nil, err
+		}
+		return append(buf, bitmap...), nil
+	case *TLSA:
+		cert, err := hex.DecodeString(v.Certificate)
+		if err != nil {
+			return nil, fmt.Errorf("wire: TLSA: invalid certificate: %w", err)
+		}
+		buf := []byte{v.Usage, v.Selector, v.MatchingType}
+		return append(buf, cert...), nil
+	case *SVCB:
+		return canonicalSVCBRData(v)
+	case *HTTPS:
+		return canonicalSVCBRData(&v.SVCB)
+	case *Unknown:
+		return append([]byte{}, v.Data...), nil
+	default:
+		return nil, fmt.Errorf("wire: no canonical encoding for RR type %T", rr)
+	}
+}
+
+// canonicalDNSKEYRData renders a DNSKEY's RDATA in wire form; it is
+// split out from canonicalRData so KeyTag computation can reuse it
+// without going through the RR interface.
+func canonicalDNSKEYRData(v *DNSKEY) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(v.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("wire: DNSKEY: invalid public key: %w", err)
+	}
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], v.Flags)
+	buf[2] = v.Protocol
+	buf[3] = v.Algorithm
+	return append(buf, key...), nil
+}
+
+// canonicalSVCBRData renders an SVCB/HTTPS RDATA: priority and target in
+// wire form, followed by each SvcParam's raw presentation-format text
+// bytes, in the order given. SvcParams are not RFC 9460 wire-encoded or
+// sorted by key, so the result is only self-consistent for records
+// goparsezone itself both signs and verifies.
+func canonicalSVCBRData(v *SVCB) ([]byte, error) {
+	buf := make([]byte, 2)
+	binary.BigEndian.PutUint16(buf, v.Priority)
+	buf = append(buf, canonicalWireName(v.Target)...)
+	for _, param := range v.SvcParams {
+		buf = append(buf, []byte(param)...)
+	}
+	return buf, nil
+}
+
+// encodeTypeBitMaps renders an NSEC/NSEC3 type bit map list in the
+// windowed wire form of RFC 4034 section 4.1.2.
+func encodeTypeBitMaps(types []string) ([]byte, error) {
+	windows := make(map[uint8][]byte)
+	for _, t := range types {
+		n, err := dnsTypeNumber(t)
+		if err != nil {
+			return nil, err
+		}
+		window := uint8(n >> 8)
+		bit := uint8(n & 0xff)
+		bm, ok := windows[window]
+		if !ok {
+			bm = make([]byte, 32)
+		}
+		bm[bit/8] |= 1 << (7 - bit%8)
+		windows[window] = bm
+	}
+
+	var order []uint8
+	for w := range windows {
+		order = append(order, w)
+	}
+	sort.Slice(order, func(i, j int) bool { return order[i] < order[j] })
+
+	var buf []byte
+	for _, w := range order {
+		bm := windows[w]
+		length := 32
+		for length > 0 && bm[length-1] == 0 {
+			length--
+		}
+		buf = append(buf, w, byte(length))
+		buf = append(buf, bm[:length]...)
+	}
+	return buf, nil
+}
+
+// canonicalOrder sorts members into RFC 4034 section 6.3's canonical
+// RRset order: ascending by canonical RDATA octets. It assumes members
+// all share the same owner name and type (i.e. already form one RRset).
+func canonicalOrder(members []DNSRecord) ([][]byte, error) {
+	rdata := make([][]byte, len(members))
+	for i, m := range members {
+		enc, err := canonicalRData(m.RR)
+		if err != nil {
+			return nil, fmt.Errorf("wire: %s %s: %w", m.Name, m.Type, err)
+		}
+		rdata[i] = enc
+	}
+	sort.Slice(rdata, func(i, j int) bool {
+		return compareBytes(rdata[i], rdata[j]) < 0
+	})
+	return rdata, nil
+}
+
+func compareBytes(a, b []byte) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return int(a[i]) - int(b[i])
+		}
+	}
+	return len(a) - len(b)
+}
@@ -0,0 +1,181 @@
+package goparsezone
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WriteTo writes z to w in the given format: "pretty" (RFC 1035 canonical
+// presentation format, grouped by owner with aligned columns), "tsv" (one
+// record per line, tab-separated: name, ttl, class, type, rdata), "json",
+// or "yaml" (both a single document with a top-level "records" array).
+func (z *Zone) WriteTo(w io.Writer, format string) error {
+	switch format {
+	case "pretty", "":
+		return z.writePretty(w)
+	case "tsv":
+		return z.writeTSV(w)
+	case "json":
+		return z.writeJSON(w)
+	case "yaml":
+		return z.writeYAML(w)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// writePretty renders the zone in RFC 1035 canonical presentation format:
+// records are grouped by owner name (in first-seen order), columns are
+// aligned, and SOA is broken across parenthesized lines the way BIND
+// writes it back out.
+func (z *Zone) writePretty(w io.Writer) error {
+	groups, owners := z.groupByOwner()
+
+	nameWidth, ttlWidth, classWidth, typeWidth := 0, 0, 0, 0
+	for _, r := range z.Records {
+		nameWidth = maxInt(nameWidth, len(r.Name))
+		ttlWidth = maxInt(ttlWidth, len(prettyTTL(r.TTL)))
+		classWidth = maxInt(classWidth, len(r.Class))
+		typeWidth = maxInt(typeWidth, len(r.Type))
+	}
+
+	for _, owner := range owners {
+		for _, r := range groups[owner] {
+			if r.Type == "SOA" {
+				if err := writePrettySOA(w, r, nameWidth, ttlWidth, classWidth); err != nil {
+					return err
+				}
+				continue
+			}
+			_, err := fmt.Fprintf(w, "%-*s %-*s %-*s %-*s %s\n",
+				nameWidth, r.Name, ttlWidth, prettyTTL(r.TTL), classWidth, r.Class, typeWidth, r.Type, rdataText(r))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writePrettySOA(w io.Writer, r DNSRecord, nameWidth, ttlWidth, classWidth int) error {
+	soa, ok := r.RR.(*SOA)
+	if !ok {
+		_, err := fmt.Fprintf(w, "%-*s %-*s %-*s SOA %s\n", nameWidth, r.Name, ttlWidth, prettyTTL(r.TTL), classWidth, r.Class, rdataText(r))
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%-*s %-*s %-*s SOA %s %s (\n"+
+		"\t\t\t\t%d ; serial\n"+
+		"\t\t\t\t%d ; refresh\n"+
+		"\t\t\t\t%d ; retry\n"+
+		"\t\t\t\t%d ; expire\n"+
+		"\t\t\t\t%d ) ; minimum\n",
+		nameWidth, r.Name, ttlWidth, prettyTTL(r.TTL), classWidth, r.Class,
+		soa.MName, soa.RName, soa.Serial, soa.Refresh, soa.Retry, soa.Expire, soa.Minimum)
+	return err
+}
+
+// groupByOwner buckets z.Records by owner name, preserving the order in
+// which each owner name was first seen.
+func (z *Zone) groupByOwner() (map[string][]DNSRecord, []string) {
+	groups := make(map[string][]DNSRecord)
+	var owners []string
+	for _, r := range z.Records {
+		if _, ok := groups[r.Name]; !ok {
+			owners = append(owners, r.Name)
+		}
+		groups[r.Name] = append(groups[r.Name], r)
+	}
+	return groups, owners
+}
+
+// prettyTTL renders a TTL as a unit-suffixed value (1h, 1d, 1w) when it
+// divides evenly, matching the form BIND itself prefers on output.
+func prettyTTL(ttl uint32) string {
+	switch {
+	case ttl != 0 && ttl%604800 == 0:
+		return fmt.Sprintf("%dw", ttl/604800)
+	case ttl != 0 && ttl%86400 == 0:
+		return fmt.Sprintf("%dd", ttl/86400)
+	case ttl != 0 && ttl%3600 == 0:
+		return fmt.Sprintf("%dh", ttl/3600)
+	default:
+		return fmt.Sprintf("%d", ttl)
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// writeTSV renders one record per line, tab-separated: name, ttl, class,
+// type, rdata, suitable for further processing with awk/cut.
+func (z *Zone) writeTSV(w io.Writer) error {
+	for _, r := range z.Records {
+		if _, err := fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", r.Name, r.TTL, r.Class, r.Type, rdataText(r)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rdataText returns the presentation-format RDATA for r, preferring the
+// typed RR's own String() (which restores quoting/escaping lost when the
+// tokenizer flattened the record into DNSRecord.RData) and falling back
+// to RData for untyped records.
+func rdataText(r DNSRecord) string {
+	if r.RR != nil {
+		return r.RR.String()
+	}
+	return r.RData
+}
+
+// zoneDocument is the structured form of a Zone used for the json and
+// yaml output formats: a single document with a top-level records array.
+type zoneDocument struct {
+	Origin     string           `json:"origin,omitempty" yaml:"origin,omitempty"`
+	DefaultTTL uint32           `json:"default_ttl,omitempty" yaml:"default_ttl,omitempty"`
+	Records    []recordDocument `json:"records" yaml:"records"`
+}
+
+type recordDocument struct {
+	Name    string `json:"name" yaml:"name"`
+	TTL     uint32 `json:"ttl" yaml:"ttl"`
+	Class   string `json:"class" yaml:"class"`
+	Type    string `json:"type" yaml:"type"`
+	RData   string `json:"rdata" yaml:"rdata"`
+	Comment string `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+func (z *Zone) document() zoneDocument {
+	doc := zoneDocument{Origin: z.Origin, DefaultTTL: z.DefaultTTL, Records: make([]recordDocument, len(z.Records))}
+	for i, r := range z.Records {
+		doc.Records[i] = recordDocument{
+			Name:    r.Name,
+			TTL:     r.TTL,
+			Class:   r.Class,
+			Type:    r.Type,
+			RData:   rdataText(r),
+			Comment: strings.Join(r.Comments, "; "),
+		}
+	}
+	return doc
+}
+
+func (z *Zone) writeJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(z.document())
+}
+
+func (z *Zone) writeYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	defer enc.Close()
+	return enc.Encode(z.document())
+}
@@ -0,0 +1,64 @@
+// Package goparsezone implements a parser for DNS master (zone) files as
+// described in RFC 1035 section 5, with the common extensions ($INCLUDE,
+// $GENERATE) supported by most production nameservers.
+package goparsezone
+
+import (
+	"fmt"
+	"os"
+)
+
+// DNSRecord is a thin header for a resource record: owner, TTL, class,
+// type, and the RDATA text as the tokenizer produced it (fields joined by
+// single spaces, with quotes stripped and escapes decoded).
+// RR holds the same RDATA parsed into a typed struct (see rr.go) for
+// record types goparsezone understands; it is nil for types it doesn't,
+// in which case RData is still available.
+type DNSRecord struct {
+	Name     string
+	TTL      uint32
+	Class    string
+	Type     string
+	RData    string
+	RR       RR
+	Origin   string
+	Line     int
+	Comments []string
+}
+
+// Zone represents a DNS zone with its records, in the order they were
+// parsed (including records synthesized by $GENERATE and records pulled in
+// via $INCLUDE).
+type Zone struct {
+	Origin     string
+	DefaultTTL uint32
+	Records    []DNSRecord
+}
+
+// ParseZone parses the DNS master file named by filename and returns the
+// resulting Zone. It is a convenience wrapper around ParseZoneReader that
+// buffers every record into memory and returns the first error encountered;
+// callers that need to stream records or continue past recoverable errors
+// should use ParseZoneReader directly.
+func ParseZone(filename string) (*Zone, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	zone, ch := parseToChan(f, "", filename)
+	var firstErr error
+	for tok := range ch {
+		if tok.Error != nil && firstErr == nil {
+			firstErr = tok.Error
+		}
+		if tok.RR != nil {
+			zone.Records = append(zone.Records, *tok.RR)
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return zone, nil
+}